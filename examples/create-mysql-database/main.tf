@@ -34,9 +34,13 @@ variable "namespace_slug" {
   description = "Namespace slug"
 }
 
-# Data sources para obtener engines y regions
+variable "region_code" {
+  type        = string
+  description = "Region code (region_code del data source filess_regions)"
+}
+
+# Data source para obtener los engines
 data "filess_engines" "all" {}
-data "filess_regions" "all" {}
 
 # Encontrar MySQL 8.0 engine
 locals {
@@ -44,9 +48,6 @@ locals {
     for engine in data.filess_engines.all.engines : engine
     if engine.name == "MySQL" && engine.version == "8.0"
   ][0]
-  
-  # Usar la primera región disponible
-  selected_region = data.filess_regions.all.regions[0]
 }
 
 # Crear base de datos MySQL con todos los billable items requeridos
@@ -57,39 +58,39 @@ resource "filess_database" "mysql_test" {
   name        = "terraform-mysql-db"
   description = "MySQL database created by Terraform provider"
   
-  engine_id = local.mysql_engine.id
-  region_id = local.selected_region.id
+  engine_id   = local.mysql_engine.id
+  region_code = var.region_code
   
   database_plan {
     # Required billable items (según /api/v1/databases/create/metadata)
     billable_items {
-      billable_item_id = "6"   # db_storage_500MiB - Storage 0.5GiB (mínimo 1, máximo 50)
-      quantity         = 1
+      name     = "db_storage_500MiB" # Storage 0.5GiB (mínimo 1, máximo 50)
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "7"   # network_bandwidth_1M - Network Bandwidth 1MB/s (mínimo 100, máximo 400)
-      quantity         = 100
+      name     = "network_bandwidth_1M" # Network Bandwidth 1MB/s (mínimo 100, máximo 400)
+      quantity = 100
     }
     
     billable_items {
-      billable_item_id = "8"   # choose_region - Choose Region (mínimo 1, máximo 1)
-      quantity         = 1
+      name     = "choose_region" # Choose Region (mínimo 1, máximo 1)
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "10"  # cpu_core_500m - CPU Core 0.25 (mínimo 1, máximo 16)
-      quantity         = 1
+      name     = "cpu_core_500m" # CPU Core 0.25 (mínimo 1, máximo 16)
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "12"  # db_instance - Database Setup (mínimo 1, máximo 1)
-      quantity         = 1
+      name     = "db_instance" # Database Setup (mínimo 1, máximo 1)
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "13"  # memory_500MiB - Memory 0.5GiB (mínimo 1, máximo 8)
-      quantity         = 1
+      name     = "memory_500MiB" # Memory 0.5GiB (mínimo 1, máximo 8)
+      quantity = 1
     }
   }
 }
@@ -115,7 +116,7 @@ output "database_version" {
 }
 
 output "database_region" {
-  value = local.selected_region.name
+  value = filess_database.mysql_test.region_code
 }
 
 output "database_hostname" {
@@ -135,3 +136,8 @@ output "database_password" {
   sensitive = true
 }
 
+output "connection_uri" {
+  value     = filess_database.mysql_test.connection_uri
+  sensitive = true
+}
+
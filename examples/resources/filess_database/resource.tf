@@ -7,16 +7,18 @@ terraform {
   }
 }
 
+variable "region_code" {
+  type        = string
+  description = "Region code (the region_code of the filess_regions data source)"
+}
+
 data "filess_engines" "all" {}
-data "filess_regions" "all" {}
 
 locals {
   mysql_engine = [
     for engine in data.filess_engines.all.engines :
     engine if engine.name == "MySQL" && engine.version == "8.0"
   ][0]
-  
-  selected_region = data.filess_regions.all.regions[0]
 }
 
 resource "filess_database" "example" {
@@ -26,38 +28,38 @@ resource "filess_database" "example" {
   name        = "example-database"
   description = "Example MySQL database"
   
-  engine_id = local.mysql_engine.id
-  region_id = local.selected_region.id
+  engine_id   = local.mysql_engine.id
+  region_code = var.region_code
   
   database_plan {
     billable_items {
-      billable_item_id = "6"   # Storage 0.5GiB
-      quantity         = 1
+      name     = "db_storage_500MiB" # Storage 0.5GiB
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "7"   # Network Bandwidth 1MB/s
-      quantity         = 100
+      name     = "network_bandwidth_1M" # Network Bandwidth 1MB/s
+      quantity = 100
     }
     
     billable_items {
-      billable_item_id = "8"   # Choose Region
-      quantity         = 1
+      name     = "choose_region" # Choose Region
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "10"  # CPU Core 0.25
-      quantity         = 1
+      name     = "cpu_core_500m" # CPU Core 0.25
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "12"  # Database Setup
-      quantity         = 1
+      name     = "db_instance" # Database Setup
+      quantity = 1
     }
     
     billable_items {
-      billable_item_id = "13"  # Memory 0.5GiB
-      quantity         = 1
+      name     = "memory_500MiB" # Memory 0.5GiB
+      quantity = 1
     }
   }
 }
@@ -67,7 +69,7 @@ output "database_id" {
 }
 
 output "connection_string" {
-  value = "mysql://${filess_database.example.database_username}:${filess_database.example.database_password}@${filess_database.example.database_hostname}:${filess_database.example.database_service_port}"
+  value     = filess_database.example.connection_uri
   sensitive = true
 }
 
@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    filess = {
+      source = "filess-io/dedicated"
+      version = ">=1.0.6"
+    }
+  }
+}
+
+variable "database_id" {
+  type        = string
+  description = "ID of the filess_database to back up"
+}
+
+# Snapshot taken before a migration, kept for 30 days
+resource "filess_database_backup" "pre_migration" {
+  database_id  = var.database_id
+  description  = "Before schema migration"
+  retain_until = timeadd(plantimestamp(), "720h")
+
+  lifecycle {
+    ignore_changes = [retain_until]
+  }
+}
+
+output "backup_size_bytes" {
+  value = filess_database_backup.pre_migration.size_bytes
+}
@@ -0,0 +1,53 @@
+terraform {
+  required_providers {
+    filess = {
+      source = "filess-io/dedicated"
+      version = ">=1.0.6"
+    }
+  }
+}
+
+resource "filess_database" "example" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name      = "example-database"
+  engine_id = "4"
+  region_id = "1"
+
+  cpu_cores      = 0.5
+  memory_gib     = 2
+  storage_gib    = 10
+  bandwidth_mbps = 100
+}
+
+resource "time_rotating" "app_password" {
+  rotation_days = 90
+}
+
+# Password generated by the API and rotated every 90 days
+resource "filess_database_user" "app" {
+  database_id = filess_database.example.id
+  username    = "app"
+  role        = "readwrite"
+
+  password_rotation_trigger = time_rotating.app_password.id
+}
+
+# Password supplied by the configuration
+resource "filess_database_user" "reporting" {
+  database_id = filess_database.example.id
+  username    = "reporting"
+  role        = "readonly"
+  password    = var.reporting_password
+}
+
+variable "reporting_password" {
+  type      = string
+  sensitive = true
+}
+
+output "app_connection_uri" {
+  value     = filess_database_user.app.connection_uri
+  sensitive = true
+}
@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    filess = {
+      source = "filess-io/dedicated"
+      version = ">=1.0.6"
+    }
+  }
+}
+
+resource "filess_ip_whitelist" "office" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name = "office"
+
+  entry {
+    cidr        = "203.0.113.0/24"
+    description = "Office network"
+  }
+
+  entry {
+    cidr        = "198.51.100.7/32"
+    description = "VPN gateway"
+  }
+}
+
+resource "filess_database" "example" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name        = "example-database"
+  engine_id   = "4"
+  region_id   = "1"
+
+  cpu_cores      = 0.5
+  memory_gib     = 2
+  storage_gib    = 10
+  bandwidth_mbps = 100
+
+  ip_whitelist_ids = [filess_ip_whitelist.office.id]
+}
@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    filess = {
+      source = "filess-io/dedicated"
+      version = ">=1.0.6"
+    }
+  }
+}
+
+resource "filess_ssh_key" "deploy" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name       = "deploy"
+  public_key = file("~/.ssh/id_ed25519.pub")
+}
+
+resource "filess_database" "example" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name      = "example-database"
+  engine_id = "4"
+  region_id = "1"
+
+  cpu_cores      = 0.5
+  memory_gib     = 2
+  storage_gib    = 10
+  bandwidth_mbps = 100
+
+  ssh_key_ids = [filess_ssh_key.deploy.id]
+}
+
+output "ssh_key_fingerprint" {
+  value = filess_ssh_key.deploy.fingerprint
+}
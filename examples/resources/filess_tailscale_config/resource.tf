@@ -0,0 +1,44 @@
+terraform {
+  required_providers {
+    filess = {
+      source = "filess-io/dedicated"
+      version = ">=1.0.6"
+    }
+  }
+}
+
+variable "tailscale_auth_key" {
+  type      = string
+  sensitive = true
+}
+
+resource "filess_tailscale_config" "main" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name            = "main-tailnet"
+  auth_key        = var.tailscale_auth_key
+  tags            = ["tag:db"]
+  hostname_prefix = "filess"
+  ephemeral       = false
+}
+
+resource "filess_database" "example" {
+  organization_slug = "my-org"
+  namespace_slug    = "production"
+
+  name      = "example-database"
+  engine_id = "4"
+  region_id = "1"
+
+  cpu_cores      = 0.5
+  memory_gib     = 2
+  storage_gib    = 10
+  bandwidth_mbps = 100
+
+  tailscale_config_id = filess_tailscale_config.main.id
+}
+
+output "database_tailnet_address" {
+  value = filess_database.example.tailscale_hostname
+}
@@ -133,6 +133,10 @@ func (c *Client) Post(path string, body interface{}) (*APIResponse, error) {
 	return c.doRequest("POST", path, body)
 }
 
+func (c *Client) Patch(path string, body interface{}) (*APIResponse, error) {
+	return c.doRequest("PATCH", path, body)
+}
+
 func (c *Client) Delete(path string) (*APIResponse, error) {
 	return c.doRequest("DELETE", path, nil)
 }
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -52,7 +52,7 @@ func DataSourceEngines() *schema.Resource {
 }
 
 func dataSourceEnginesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*meta.Meta).Client
 
 	resp, err := c.Get("/api/v1/engines")
 	if err != nil {
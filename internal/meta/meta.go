@@ -0,0 +1,23 @@
+package meta
+
+import (
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/notify"
+)
+
+// Modos de facturación soportados cuando la API devuelve un Stripe checkout
+const (
+	BillingModeWait     = "wait"
+	BillingModeFailFast = "fail_fast"
+	BillingModeAsync    = "async"
+)
+
+var BillingModes = []string{BillingModeWait, BillingModeFailFast, BillingModeAsync}
+
+// Meta es lo que el provider entrega a recursos y data sources: el cliente de
+// la API junto con la configuración del provider que no es de transporte
+type Meta struct {
+	*client.Client
+	BillingMode string
+	Notifier    *notify.Notifier
+}
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// PaymentRequired es lo que se envía a cada sink cuando una base de datos queda
+// bloqueada en un Stripe checkout
+type PaymentRequired struct {
+	DatabaseID   string `json:"database_id"`
+	DatabaseName string `json:"database_name"`
+	CheckoutURL  string `json:"checkout_url"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	BillingMode  string `json:"billing_mode"`
+}
+
+type Sink interface {
+	Name() string
+	NotifyPaymentRequired(ctx context.Context, p PaymentRequired) error
+}
+
+// Notifier reparte cada notificación entre todos los sinks configurados
+type Notifier struct {
+	Sinks []Sink
+}
+
+// NewTTYNotifier devuelve el notifier por defecto, que solo imprime en /dev/tty
+func NewTTYNotifier() *Notifier {
+	return &Notifier{Sinks: []Sink{&TTYSink{}}}
+}
+
+// NotifyPaymentRequired envía la notificación a todos los sinks y devuelve los
+// errores de los que fallaron, para que un sink roto no oculte a los demás
+func (n *Notifier) NotifyPaymentRequired(ctx context.Context, p PaymentRequired) []error {
+	var errs []error
+	for _, sink := range n.Sinks {
+		if err := sink.NotifyPaymentRequired(ctx, p); err != nil {
+			errs = append(errs, fmt.Errorf("%s notification failed: %w", sink.Name(), err))
+		}
+	}
+	return errs
+}
+
+// TTYSink imprime directamente a /dev/tty para que sea visible sin TF_LOG
+type TTYSink struct{}
+
+func (s *TTYSink) Name() string { return "tty" }
+
+func (s *TTYSink) NotifyPaymentRequired(ctx context.Context, p PaymentRequired) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		// Sin terminal (CI, Terraform Cloud...) no hay nada que mostrar
+		return nil
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "\n")
+	fmt.Fprintf(tty, "╔════════════════════════════════════════════════════════════╗\n")
+	fmt.Fprintf(tty, "║  ⚠️  PAYMENT REQUIRED                                      ║\n")
+	fmt.Fprintf(tty, "╚════════════════════════════════════════════════════════════╝\n")
+	fmt.Fprintf(tty, "\n")
+	fmt.Fprintf(tty, "The database requires payment to continue provisioning.\n")
+	fmt.Fprintf(tty, "Please open this URL to complete the Stripe checkout:\n\n")
+	fmt.Fprintf(tty, "  %s\n\n", p.CheckoutURL)
+	if p.ExpiresAt != "" {
+		fmt.Fprintf(tty, "The checkout expires at %s.\n", p.ExpiresAt)
+	}
+	if p.BillingMode == "wait" {
+		fmt.Fprintf(tty, "Waiting for payment completion...\n")
+	}
+	fmt.Fprintf(tty, "\n")
+	return nil
+}
+
+// FileSink añade una línea JSON por notificación a un fichero local
+type FileSink struct {
+	Path string
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+func (s *FileSink) NotifyPaymentRequired(ctx context.Context, p PaymentRequired) error {
+	line, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookSink envía la notificación como JSON en un POST a una URL
+type WebhookSink struct {
+	URL        string
+	Headers    map[string]string
+	HTTPClient *http.Client
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) NotifyPaymentRequired(ctx context.Context, p PaymentRequired) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// CommandSink ejecuta un comando local con la notificación como JSON en stdin y
+// como variables de entorno FILESS_*
+type CommandSink struct {
+	Command []string
+}
+
+func (s *CommandSink) Name() string { return "command" }
+
+func (s *CommandSink) NotifyPaymentRequired(ctx context.Context, p PaymentRequired) error {
+	if len(s.Command) == 0 {
+		return fmt.Errorf("command is empty")
+	}
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"FILESS_DATABASE_ID="+p.DatabaseID,
+		"FILESS_DATABASE_NAME="+p.DatabaseName,
+		"FILESS_CHECKOUT_URL="+p.CheckoutURL,
+		"FILESS_CHECKOUT_EXPIRES_AT="+p.ExpiresAt,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
@@ -5,8 +5,11 @@ import (
 
 	"github.com/filess/terraform-provider-dedicated/internal/client"
 	"github.com/filess/terraform-provider-dedicated/internal/datasources"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/filess/terraform-provider-dedicated/internal/notify"
 	"github.com/filess/terraform-provider-dedicated/internal/resources"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
@@ -24,9 +27,64 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("FILESS_API_URL", "https://backend.filess.io"),
 				Description: "Base URL for filess.io API",
 			},
+			"billing_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("FILESS_BILLING_MODE", meta.BillingModeWait),
+				ValidateFunc: validation.StringInSlice(meta.BillingModes, false),
+				Description:  "Default behavior when a database requires a Stripe checkout: `wait` blocks until payment completes, `fail_fast` errors immediately with the checkout URL, `async` records the URL and finishes provisioning on the next apply",
+			},
+			"payment_notification": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Where to send the Stripe checkout URL when a database requires payment. Defaults to printing it to the terminal",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tty": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Print the checkout URL to /dev/tty",
+						},
+						"file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Path of a local file where a JSON line is appended for each notification",
+						},
+						"webhook_url": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "URL that receives a JSON POST with the database ID, checkout URL and expiry",
+						},
+						"webhook_headers": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Additional HTTP headers sent with the webhook request",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"command": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Local command to run, receiving the notification as JSON on stdin and as FILESS_* environment variables",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"filess_database": resources.ResourceDatabase(),
+			"filess_database":         resources.ResourceDatabase(),
+			"filess_database_backup":  resources.ResourceDatabaseBackup(),
+			"filess_database_user":    resources.ResourceDatabaseUser(),
+			"filess_ip_whitelist":     resources.ResourceIPWhitelist(),
+			"filess_ssh_key":          resources.ResourceSSHKey(),
+			"filess_tailscale_config": resources.ResourceTailscaleConfig(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"filess_engines": datasources.DataSourceEngines(),
@@ -50,5 +108,48 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, fmt.Errorf("api_url cannot be empty")
 	}
 
-	return client.NewClient(apiURL, apiToken), nil
+	notifier, err := expandPaymentNotification(d.Get("payment_notification").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &meta.Meta{
+		Client:      client.NewClient(apiURL, apiToken),
+		BillingMode: d.Get("billing_mode").(string),
+		Notifier:    notifier,
+	}, nil
+}
+
+func expandPaymentNotification(raw []interface{}) (*notify.Notifier, error) {
+	notifier := &notify.Notifier{}
+	if len(raw) == 0 || raw[0] == nil {
+		return notify.NewTTYNotifier(), nil
+	}
+
+	cfg := raw[0].(map[string]interface{})
+	if cfg["tty"].(bool) {
+		notifier.Sinks = append(notifier.Sinks, &notify.TTYSink{})
+	}
+	if path := cfg["file"].(string); path != "" {
+		notifier.Sinks = append(notifier.Sinks, &notify.FileSink{Path: path})
+	}
+	if url := cfg["webhook_url"].(string); url != "" {
+		headers := map[string]string{}
+		for k, v := range cfg["webhook_headers"].(map[string]interface{}) {
+			headers[k] = v.(string)
+		}
+		notifier.Sinks = append(notifier.Sinks, &notify.WebhookSink{URL: url, Headers: headers})
+	}
+	if command := cfg["command"].([]interface{}); len(command) > 0 {
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i], _ = arg.(string)
+		}
+		if args[0] == "" {
+			return nil, fmt.Errorf("payment_notification.command must start with the executable to run")
+		}
+		notifier.Sinks = append(notifier.Sinks, &notify.CommandSink{Command: args})
+	}
+
+	return notifier, nil
 }
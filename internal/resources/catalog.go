@@ -0,0 +1,355 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/hashicorp/go-version"
+)
+
+// Número máximo de alternativas sugeridas en los mensajes de error
+const maxCatalogSuggestions = 3
+
+type engineInfo struct {
+	ID      string
+	Name    string
+	Version string
+	Slug    string
+	Active  bool
+}
+
+func (e engineInfo) String() string {
+	return fmt.Sprintf("%q (%s %s)", e.ID, e.Name, e.Version)
+}
+
+type regionInfo struct {
+	ID         string
+	Name       string
+	RegionCode string
+}
+
+func (r regionInfo) String() string {
+	return fmt.Sprintf("%q (%s, %s)", r.ID, r.Name, r.RegionCode)
+}
+
+// catalogCache guarda los catálogos obtenidos por una instancia del provider,
+// que no cambian durante un plan o apply, para no pedirlos otra vez en el
+// refresh de cada recurso
+type catalogCache struct {
+	mu       sync.Mutex
+	engines  []engineInfo
+	regions  []regionInfo
+	metadata map[string]*databaseCreateMetadata
+}
+
+// Una caché por cliente, es decir, por instancia configurada del proveedor
+var catalogCaches sync.Map
+
+func catalogCacheFor(c *client.Client) *catalogCache {
+	cache, _ := catalogCaches.LoadOrStore(c, &catalogCache{})
+	return cache.(*catalogCache)
+}
+
+func listEngines(c *client.Client) ([]engineInfo, error) {
+	cache := catalogCacheFor(c)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.engines != nil {
+		return cache.engines, nil
+	}
+
+	resp, err := c.Get("/api/v1/engines")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := resp.Data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected engines response format")
+	}
+
+	engines := make([]engineInfo, 0, len(raw))
+	for _, item := range raw {
+		e, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := e["name"].(string)
+		engineVersion, _ := e["version"].(string)
+		slug, _ := e["slug"].(string)
+		active, _ := e["active"].(bool)
+		engines = append(engines, engineInfo{
+			ID:      idToString(e["id"]),
+			Name:    name,
+			Version: engineVersion,
+			Slug:    slug,
+			Active:  active,
+		})
+	}
+
+	cache.engines = engines
+	return engines, nil
+}
+
+func listRegions(c *client.Client) ([]regionInfo, error) {
+	cache := catalogCacheFor(c)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.regions != nil {
+		return cache.regions, nil
+	}
+
+	resp, err := c.Get("/api/v1/regions")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := resp.Data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected regions response format")
+	}
+
+	regions := make([]regionInfo, 0, len(raw))
+	for _, item := range raw {
+		r, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := r["name"].(string)
+		regionCode, _ := r["regionCode"].(string)
+		regions = append(regions, regionInfo{
+			ID:         idToString(r["id"]),
+			Name:       name,
+			RegionCode: regionCode,
+		})
+	}
+
+	cache.regions = regions
+	return regions, nil
+}
+
+// checkEngineID comprueba que el engine existe y está activo
+func checkEngineID(engines []engineInfo, id string) error {
+	for _, e := range engines {
+		if e.ID != id {
+			continue
+		}
+		if e.Active {
+			return nil
+		}
+		return fmt.Errorf("engine_id: engine %s is not active%s", e, didYouMean(suggestEngines(engines, id, e.Slug)))
+	}
+
+	return fmt.Errorf("engine_id: unknown engine %q%s", id, didYouMean(suggestEngines(engines, id, "")))
+}
+
+// resolveEngine elige el engine activo de mayor versión con ese slug que cumple
+// la restricción de versión (cualquiera si está vacía)
+func resolveEngine(engines []engineInfo, slug, constraint string) (engineInfo, error) {
+	var constraints version.Constraints
+	if constraint != "" {
+		var err error
+		constraints, err = version.NewConstraint(constraint)
+		if err != nil {
+			return engineInfo{}, fmt.Errorf("engine_version: invalid version constraint %q: %w", constraint, err)
+		}
+	}
+
+	var best engineInfo
+	var bestVersion *version.Version
+	var available []string
+	for _, e := range engines {
+		if !e.Active || !strings.EqualFold(e.Slug, slug) {
+			continue
+		}
+		available = append(available, e.Version)
+
+		v, err := version.NewVersion(e.Version)
+		if err != nil {
+			continue
+		}
+		if constraints != nil && !constraints.Check(v) {
+			continue
+		}
+		if bestVersion == nil || v.GreaterThan(bestVersion) {
+			best, bestVersion = e, v
+		}
+	}
+
+	if bestVersion != nil {
+		return best, nil
+	}
+	if len(available) == 0 {
+		return engineInfo{}, fmt.Errorf("engine_slug: no active engine with slug %q%s", slug, didYouMean(suggestEngineSlugs(engines, slug)))
+	}
+	return engineInfo{}, fmt.Errorf("engine_version: no active %s version satisfies %q, available versions are: %s", slug, constraint, strings.Join(available, ", "))
+}
+
+// engineSatisfies indica si el engine cumple el slug y la restricción de versión
+func engineSatisfies(e engineInfo, slug, constraint string) bool {
+	if !e.Active || !strings.EqualFold(e.Slug, slug) {
+		return false
+	}
+	if constraint == "" {
+		return true
+	}
+
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	v, err := version.NewVersion(e.Version)
+	return err == nil && constraints.Check(v)
+}
+
+func suggestEngineSlugs(engines []engineInfo, slug string) []string {
+	seen := map[string]bool{}
+	var slugs []string
+	for _, e := range engines {
+		if e.Active && e.Slug != "" && !seen[e.Slug] {
+			seen[e.Slug] = true
+			slugs = append(slugs, e.Slug)
+		}
+	}
+
+	sort.SliceStable(slugs, func(i, j int) bool {
+		return levenshtein(slug, slugs[i]) < levenshtein(slug, slugs[j])
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(slugs) && i < maxCatalogSuggestions; i++ {
+		suggestions = append(suggestions, fmt.Sprintf("%q", slugs[i]))
+	}
+	return suggestions
+}
+
+// checkRegionID comprueba que la región existe
+func checkRegionID(regions []regionInfo, id string) error {
+	for _, r := range regions {
+		if r.ID == id {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("region_id: unknown region %q%s", id, didYouMean(suggestRegions(regions, id)))
+}
+
+// findRegionByCode busca la región con ese regionCode
+func findRegionByCode(regions []regionInfo, code string) (regionInfo, error) {
+	for _, r := range regions {
+		if strings.EqualFold(r.RegionCode, code) {
+			return r, nil
+		}
+	}
+
+	codes := make([]string, 0, len(regions))
+	for _, r := range regions {
+		if r.RegionCode != "" {
+			codes = append(codes, r.RegionCode)
+		}
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return levenshtein(code, codes[i]) < levenshtein(code, codes[j])
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(codes) && i < maxCatalogSuggestions; i++ {
+		suggestions = append(suggestions, fmt.Sprintf("%q", codes[i]))
+	}
+	return regionInfo{}, fmt.Errorf("region_code: unknown region code %q%s", code, didYouMean(suggestions))
+}
+
+// databaseRegionCode obtiene el código de la región de la respuesta o, si no
+// viene incluido, del catálogo de regiones
+func databaseRegionCode(c *client.Client, data map[string]interface{}) (string, error) {
+	if region, ok := data["region"].(map[string]interface{}); ok {
+		if code, _ := region["regionCode"].(string); code != "" {
+			return code, nil
+		}
+	}
+
+	regionId := idToString(data["regionId"])
+	regions, err := listRegions(c)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range regions {
+		if r.ID == regionId {
+			return r.RegionCode, nil
+		}
+	}
+	return "", nil
+}
+
+// suggestEngines devuelve los engines activos más parecidos, priorizando los
+// del mismo slug (otra versión del mismo motor)
+func suggestEngines(engines []engineInfo, id, slug string) []string {
+	var candidates []engineInfo
+	for _, e := range engines {
+		if e.Active {
+			candidates = append(candidates, e)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := slug != "" && candidates[i].Slug == slug, slug != "" && candidates[j].Slug == slug
+		if si != sj {
+			return si
+		}
+		return levenshtein(id, candidates[i].ID) < levenshtein(id, candidates[j].ID)
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < maxCatalogSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].String())
+	}
+	return suggestions
+}
+
+func suggestRegions(regions []regionInfo, id string) []string {
+	candidates := append([]regionInfo(nil), regions...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return levenshtein(id, candidates[i].ID) < levenshtein(id, candidates[j].ID)
+	})
+
+	suggestions := []string{}
+	for i := 0; i < len(candidates) && i < maxCatalogSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].String())
+	}
+	return suggestions
+}
+
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return ", did you mean " + strings.Join(suggestions, " or ") + "?"
+}
+
+// levenshtein calcula la distancia de edición entre dos cadenas
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+
+	return prev[len(b)]
+}
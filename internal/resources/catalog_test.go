@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveEngine(t *testing.T) {
+	engines := []engineInfo{
+		{ID: "1", Name: "MySQL", Version: "5.7", Slug: "mysql", Active: true},
+		{ID: "2", Name: "MySQL", Version: "8.0", Slug: "mysql", Active: true},
+		{ID: "3", Name: "MySQL", Version: "8.4", Slug: "mysql", Active: false},
+		{ID: "4", Name: "PostgreSQL", Version: "15", Slug: "postgresql", Active: true},
+		{ID: "5", Name: "PostgreSQL", Version: "16", Slug: "postgresql", Active: true},
+		{ID: "6", Name: "MariaDB", Version: "10.11", Slug: "mariadb", Active: true},
+	}
+
+	cases := []struct {
+		desc       string
+		slug       string
+		constraint string
+		wantID     string
+		wantErr    string
+	}{
+		{
+			desc:   "highest active version",
+			slug:   "mysql",
+			wantID: "2",
+		},
+		{
+			desc:   "case insensitive slug",
+			slug:   "PostgreSQL",
+			wantID: "5",
+		},
+		{
+			desc:       "exact version",
+			slug:       "postgresql",
+			constraint: "15",
+			wantID:     "4",
+		},
+		{
+			desc:       "pessimistic constraint",
+			slug:       "mysql",
+			constraint: "~> 5.7",
+			wantID:     "1",
+		},
+		{
+			desc:       "inactive versions are ignored",
+			slug:       "mysql",
+			constraint: ">= 8.1",
+			wantErr:    `engine_version: no active mysql version satisfies ">= 8.1", available versions are: 5.7, 8.0`,
+		},
+		{
+			desc:       "invalid constraint",
+			slug:       "mysql",
+			constraint: "latest",
+			wantErr:    `engine_version: invalid version constraint "latest"`,
+		},
+		{
+			desc:    "unknown slug with suggestion",
+			slug:    "postgres",
+			wantErr: `engine_slug: no active engine with slug "postgres", did you mean "postgresql"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			engine, err := resolveEngine(engines, tc.slug, tc.constraint)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if engine.ID != tc.wantID {
+				t.Errorf("resolved engine %s, want %q", engine, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"mysql", "mysql", 0},
+		{"postgres", "postgresql", 2},
+		{"eu-west-1", "eu-west-2", 1},
+		{"kitten", "sitting", 3},
+		{"mariadb", "mysql", 6},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			if got := levenshtein(tc.a, tc.b); got != tc.want {
+				t.Errorf("levenshtein(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
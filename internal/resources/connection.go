@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+)
+
+// connectionScheme describe cómo construir las cadenas de conexión de un engine
+type connectionScheme struct {
+	Scheme     string
+	JDBCScheme string
+	Query      url.Values
+	JDBCQuery  url.Values
+}
+
+// connectionSchemeForEngine devuelve el esquema por defecto del engine según su
+// slug (mysql, mariadb, postgresql, mongodb, redis...)
+func connectionSchemeForEngine(slug string) (connectionScheme, bool) {
+	slug = strings.ToLower(slug)
+	switch {
+	case strings.HasPrefix(slug, "mariadb"):
+		return connectionScheme{
+			Scheme:     "mysql",
+			JDBCScheme: "jdbc:mariadb",
+			Query:      url.Values{"tls": {"true"}},
+			JDBCQuery:  url.Values{"sslMode": {"trust"}},
+		}, true
+	case strings.HasPrefix(slug, "mysql"):
+		return connectionScheme{
+			Scheme:     "mysql",
+			JDBCScheme: "jdbc:mysql",
+			Query:      url.Values{"tls": {"true"}},
+			JDBCQuery:  url.Values{"sslMode": {"REQUIRED"}},
+		}, true
+	case strings.HasPrefix(slug, "postgres"):
+		return connectionScheme{
+			Scheme:     "postgresql",
+			JDBCScheme: "jdbc:postgresql",
+			Query:      url.Values{"sslmode": {"require"}},
+			JDBCQuery:  url.Values{"sslmode": {"require"}},
+		}, true
+	case strings.HasPrefix(slug, "mongo"):
+		return connectionScheme{
+			Scheme: "mongodb",
+			Query:  url.Values{"tls": {"true"}, "authSource": {"admin"}},
+		}, true
+	case strings.HasPrefix(slug, "redis"):
+		return connectionScheme{
+			Scheme: "rediss",
+		}, true
+	}
+	return connectionScheme{}, false
+}
+
+// buildConnectionURIs construye la URI de conexión y, para los engines con
+// driver JDBC, la URL JDBC. Las credenciales van escapadas
+func buildConnectionURIs(slug, hostname, port, username, password string) (string, string) {
+	scheme, ok := connectionSchemeForEngine(slug)
+	if !ok || hostname == "" || port == "" {
+		return "", ""
+	}
+
+	host := net.JoinHostPort(hostname, port)
+
+	uri := &url.URL{
+		Scheme:   scheme.Scheme,
+		Host:     host,
+		Path:     "/",
+		RawQuery: scheme.Query.Encode(),
+	}
+	if username != "" {
+		uri.User = url.UserPassword(username, password)
+	}
+
+	if scheme.JDBCScheme == "" {
+		return uri.String(), ""
+	}
+
+	// JDBC no admite credenciales en la autoridad, van como parámetros
+	jdbcQuery := url.Values{}
+	for k, v := range scheme.JDBCQuery {
+		jdbcQuery[k] = v
+	}
+	if username != "" {
+		jdbcQuery.Set("user", username)
+		jdbcQuery.Set("password", password)
+	}
+	jdbc := scheme.JDBCScheme + "://" + host + "/?" + jdbcQuery.Encode()
+
+	return uri.String(), jdbc
+}
+
+// databaseEngineSlug obtiene el slug del engine de la respuesta o, si no viene
+// incluido, del catálogo de engines
+func databaseEngineSlug(c *client.Client, data map[string]interface{}) (string, error) {
+	if engine, ok := data["engine"].(map[string]interface{}); ok {
+		if slug, _ := engine["slug"].(string); slug != "" {
+			return slug, nil
+		}
+	}
+
+	engineId := idToString(data["engineId"])
+	engines, err := listEngines(c)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range engines {
+		if e.ID == engineId {
+			return e.Slug, nil
+		}
+	}
+	return "", nil
+}
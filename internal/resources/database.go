@@ -2,23 +2,45 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/filess/terraform-provider-dedicated/internal/notify"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// Estados en los que la base de datos todavía se está provisionando
+var databasePendingStatuses = []string{"creating", "deploying", "waiting_credentials", "billing_pending", "scaling"}
+
+// Estados en los que el backend dio la provisión por fallida
+var databaseFailedStatuses = []string{"failed", "error", "cancelled"}
+
 func ResourceDatabase() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceDatabaseCreate,
 		ReadContext:   resourceDatabaseRead,
 		UpdateContext: resourceDatabaseUpdate,
 		DeleteContext: resourceDatabaseDelete,
+		CustomizeDiff: resourceDatabaseCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"organization_slug": {
 				Type:        schema.TypeString,
@@ -43,22 +65,53 @@ func ResourceDatabase() *schema.Resource {
 				Description: "Database description",
 			},
 			"engine_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Database engine ID",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"engine_id", "engine_slug"},
+				Description:  "Database engine ID. Conflicts with `engine_slug`",
+			},
+			"engine_slug": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				// engine_id decide si hay que reemplazar la base de datos
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+				Description: "Engine slug (e.g. `mysql`), resolved to `engine_id` at plan time. Conflicts with `engine_id`",
+			},
+			"engine_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"engine_slug"},
+				Description:  "Version or version constraint (e.g. `8.0`, `~> 8.0`) used with `engine_slug`. The highest matching active version is selected",
 			},
 			"region_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Region ID",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"region_id", "region_code"},
+				Description:  "Region ID. Conflicts with `region_code`",
+			},
+			"region_code": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				// region_id decide si hay que reemplazar la base de datos
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new)
+				},
+				Description: "Region code (the `region_code` of `filess_regions`), resolved to `region_id` at plan time. Conflicts with `region_id`",
 			},
 			"database_plan": {
 				Type:        schema.TypeList,
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 				MaxItems:    1,
-				Description: "Database plan configuration",
+				Description: "Database plan configuration. Computed from the sizing attributes when `cpu_cores`, `memory_gib`, `storage_gib` and `bandwidth_mbps` are used instead",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"billable_items": {
@@ -69,8 +122,15 @@ func ResourceDatabase() *schema.Resource {
 								Schema: map[string]*schema.Schema{
 									"billable_item_id": {
 										Type:        schema.TypeString,
-										Required:    true,
-										Description: "Billable item ID",
+										Optional:    true,
+										Computed:    true,
+										Description: "Billable item ID. Either `billable_item_id` or `name` must be set",
+									},
+									"name": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Computed:    true,
+										Description: "Billable item name (e.g. `cpu_core_500m`, `db_storage_500MiB`), resolved against the create metadata. Either `billable_item_id` or `name` must be set",
 									},
 									"quantity": {
 										Type:        schema.TypeInt,
@@ -83,18 +143,50 @@ func ResourceDatabase() *schema.Resource {
 					},
 				},
 			},
+			"cpu_cores": {
+				Type:          schema.TypeFloat,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"database_plan"},
+				RequiredWith:  []string{"cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps"},
+				Description:   "CPU cores (e.g. `0.5`). With `memory_gib`, `storage_gib` and `bandwidth_mbps`, an alternative to `database_plan`",
+			},
+			"memory_gib": {
+				Type:          schema.TypeFloat,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"database_plan"},
+				RequiredWith:  []string{"cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps"},
+				Description:   "Memory in GiB. With `cpu_cores`, `storage_gib` and `bandwidth_mbps`, an alternative to `database_plan`",
+			},
+			"storage_gib": {
+				Type:          schema.TypeFloat,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"database_plan"},
+				RequiredWith:  []string{"cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps"},
+				Description:   "Storage in GiB. With `cpu_cores`, `memory_gib` and `bandwidth_mbps`, an alternative to `database_plan`",
+			},
+			"bandwidth_mbps": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"database_plan"},
+				RequiredWith:  []string{"cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps"},
+				Description:   "Network bandwidth in Mbps. With `cpu_cores`, `memory_gib` and `storage_gib`, an alternative to `database_plan`",
+			},
 			"ip_whitelist_ids": {
-				Type:        schema.TypeList,
+				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "List of IP whitelist IDs",
+				Description: "Set of IP whitelist IDs (see `filess_ip_whitelist`)",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
 			"ssh_key_ids": {
-				Type:        schema.TypeList,
+				Type:        schema.TypeSet,
 				Optional:    true,
-				Description: "List of SSH key IDs",
+				Description: "Set of SSH key IDs (see `filess_ssh_key`)",
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -102,7 +194,30 @@ func ResourceDatabase() *schema.Resource {
 			"tailscale_config_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Tailscale config ID",
+				Description: "Tailscale config ID (see `filess_tailscale_config`)",
+			},
+			"billing_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(meta.BillingModes, false),
+				Description:  "Behavior when a Stripe checkout is required: `wait`, `fail_fast` or `async`. Defaults to the provider `billing_mode`",
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether Terraform is prevented from destroying the database. Must be set to `false` and applied before the database can be destroyed or replaced",
+			},
+			"cleanup_on_failure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the database automatically when the backend reports that provisioning failed",
+			},
+			"password_rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value (e.g. a timestamp) whose change rotates the root user's password in place",
 			},
 			"status": {
 				Type:        schema.TypeString,
@@ -130,11 +245,63 @@ func ResourceDatabase() *schema.Resource {
 				Sensitive:   true,
 				Description: "Database password to use when connecting",
 			},
+			"connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Connection URI for the engine (e.g. `mysql://`, `postgresql://`, `mongodb://`, `rediss://`) with the URL-escaped credentials of `database_username`",
+			},
+			"jdbc_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "JDBC URL with credentials for engines that have a JDBC driver (MySQL, MariaDB, PostgreSQL), empty otherwise",
+			},
+			"users": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All database users returned by the API",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"username": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Username",
+						},
+						"role": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Role of the user (e.g. root)",
+						},
+						"password": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Sensitive:   true,
+							Description: "Password of the user",
+						},
+					},
+				},
+			},
 			"stripe_checkout_url": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Stripe checkout URL to complete billing when required",
 			},
+			"provisioning_pending": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether provisioning was interrupted after the database was created. The next apply resumes waiting for it, or replaces it if the backend reports it failed",
+			},
+			"tailscale_hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "MagicDNS name of the database in the tailnet when `tailscale_config_id` is set",
+			},
+			"tailscale_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Tailnet IP address of the database when `tailscale_config_id` is set",
+			},
 			"created_at": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -145,23 +312,11 @@ func ResourceDatabase() *schema.Resource {
 }
 
 func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	providerMeta := m.(*meta.Meta)
+	c := providerMeta.Client
 	var diags diag.Diagnostics
 
 	// Preparar el request body
-	plan := d.Get("database_plan").([]interface{})[0].(map[string]interface{})
-	billableItemsSet := plan["billable_items"].(*schema.Set)
-	billableItems := billableItemsSet.List()
-
-	databasePlanBI := make([]map[string]interface{}, len(billableItems))
-	for i, item := range billableItems {
-		itemMap := item.(map[string]interface{})
-		databasePlanBI[i] = map[string]interface{}{
-			"billableItemId": itemMap["billable_item_id"].(string),
-			"quantity":       itemMap["quantity"].(int),
-		}
-	}
-
 	requestBody := map[string]interface{}{
 		"organizationSlug": d.Get("organization_slug").(string),
 		"namespaceSlug":    d.Get("namespace_slug").(string),
@@ -172,16 +327,17 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 			"description": d.Get("description").(string),
 		},
 		"databasePlanDetails": map[string]interface{}{
-			"databasePlanBI": databasePlanBI,
+			"databasePlanBI": expandDatabasePlanItems(d.Get("database_plan")),
 		},
+		"deletionProtection": d.Get("deletion_protection").(bool),
 	}
 
 	if v, ok := d.GetOk("ip_whitelist_ids"); ok {
-		requestBody["ipWhitelistIds"] = v.([]interface{})
+		requestBody["ipWhitelistIds"] = v.(*schema.Set).List()
 	}
 
 	if v, ok := d.GetOk("ssh_key_ids"); ok {
-		requestBody["sshKeyIds"] = v.([]interface{})
+		requestBody["sshKeyIds"] = v.(*schema.Set).List()
 	}
 
 	if v, ok := d.GetOk("tailscale_config_id"); ok {
@@ -196,52 +352,45 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 	// Extraer el ID de la base de datos creada
 	data := resp.Data.(map[string]interface{})
 	database := data["database"].(map[string]interface{})
-	// Manejar id que puede ser string o float64
-	var databaseId string
-	switch v := database["id"].(type) {
-	case float64:
-		databaseId = fmt.Sprintf("%.0f", v)
-	case string:
-		databaseId = v
-	default:
-		databaseId = fmt.Sprintf("%v", v)
-	}
+	databaseId := idToString(database["id"])
 
 	d.SetId(databaseId)
 
+	// Marcar la provisión como pendiente hasta que termine, para poder
+	// retomarla en el siguiente apply si se interrumpe
+	d.Set("provisioning_pending", true)
+
 	if url := extractStripeCheckoutURL(data); url != "" {
-		// Imprimir directamente a /dev/tty para que sea visible sin TF_LOG
-		if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
-			fmt.Fprintf(tty, "\n")
-			fmt.Fprintf(tty, "╔════════════════════════════════════════════════════════════╗\n")
-			fmt.Fprintf(tty, "║  ⚠️  PAYMENT REQUIRED                                      ║\n")
-			fmt.Fprintf(tty, "╚════════════════════════════════════════════════════════════╝\n")
-			fmt.Fprintf(tty, "\n")
-			fmt.Fprintf(tty, "The database requires payment to continue provisioning.\n")
-			fmt.Fprintf(tty, "Please open this URL to complete the Stripe checkout:\n\n")
-			fmt.Fprintf(tty, "  %s\n\n", url)
-			fmt.Fprintf(tty, "Waiting for payment completion...\n")
-			fmt.Fprintf(tty, "\n")
-			tty.Close()
-		}
-
-		tflog.Info(ctx, "Database provisioning blocked until Stripe checkout completes", map[string]interface{}{
-			"stripe_checkout_url": url,
-			"database_id":         databaseId,
-		})
-		if err := d.Set("stripe_checkout_url", url); err != nil {
-			return diag.FromErr(err)
+		// Con fail_fast la base de datos se conserva con provisioning_pending
+		// para retomar la provisión cuando se haya pagado
+		wait, checkoutDiags := handleStripeCheckout(ctx, d, providerMeta, data)
+		diags = append(diags, checkoutDiags...)
+		if checkoutDiags.HasError() {
+			return diags
+		}
+		if !wait {
+			return append(diags, resourceDatabaseRead(ctx, d, m)...)
 		}
+	}
+
+	progress := newProvisioningProgress(databaseId)
+	if _, err := waitForDatabaseCredentials(ctx, c, databaseId, d.Timeout(schema.TimeoutCreate), progress); err != nil {
+		diags = append(diags, progress.slowDiagnostics()...)
+		if !isResumableWaitError(ctx, err) {
+			return append(diags, handleProvisioningError(ctx, d, c, err)...)
+		}
+
+		// No devolver error para que el recurso no quede tainted y el
+		// siguiente apply retome la espera en lugar de recrearlo
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Warning,
-			Summary:  "Payment required",
-			Detail:   fmt.Sprintf("Open the checkout URL to complete billing and resume provisioning: %s", url),
+			Summary:  "Database provisioning interrupted",
+			Detail:   fmt.Sprintf("Stopped waiting for database %s: %s. The database was created and the next apply will resume waiting for it instead of recreating it.", databaseId, err),
 		})
+		return append(diags, resourceDatabaseRead(ctx, d, m)...)
 	}
-
-	if _, err := waitForDatabaseCredentials(ctx, c, databaseId); err != nil {
-		return diag.FromErr(err)
-	}
+	d.Set("provisioning_pending", false)
+	diags = append(diags, progress.slowDiagnostics()...)
 
 	readDiags := resourceDatabaseRead(ctx, d, m)
 	diags = append(diags, readDiags...)
@@ -249,7 +398,7 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*meta.Meta).Client
 
 	resp, err := c.Get("/api/v1/databases/" + d.Id())
 	if err != nil {
@@ -262,23 +411,32 @@ func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, m interfa
 
 	data := resp.Data.(map[string]interface{})
 
-	// Helper para convertir id a string
-	idToString := func(v interface{}) string {
-		switch val := v.(type) {
-		case float64:
-			return fmt.Sprintf("%.0f", val)
-		case string:
-			return val
-		default:
-			return fmt.Sprintf("%v", val)
-		}
-	}
-
 	d.Set("name", data["name"])
 	d.Set("description", data["description"])
 	d.Set("status", data["status"])
-	d.Set("engine_id", idToString(data["engineId"]))
-	d.Set("region_id", idToString(data["regionId"]))
+
+	if err := setDatabaseIdentity(ctx, d, c, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("ip_whitelist_ids", flattenIDList(data["ipWhitelistIds"]))
+	d.Set("ssh_key_ids", flattenIDList(data["sshKeyIds"]))
+	if v, ok := data["tailscaleConfigId"]; ok && v != nil {
+		d.Set("tailscale_config_id", idToString(v))
+	} else {
+		d.Set("tailscale_config_id", "")
+	}
+
+	tailscale, _ := data["tailscale"].(map[string]interface{})
+	tailscaleHostname, _ := tailscale["hostname"].(string)
+	tailscaleIP, _ := tailscale["ipAddress"].(string)
+	d.Set("tailscale_hostname", tailscaleHostname)
+	d.Set("tailscale_ip", tailscaleIP)
+
+	// Solo disponible si el backend soporta la protección contra borrado
+	if v, ok := data["deletionProtection"].(bool); ok {
+		d.Set("deletion_protection", v)
+	}
 
 	if createdAt, ok := data["createdAt"]; ok {
 		d.Set("created_at", createdAt)
@@ -297,18 +455,129 @@ func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, m interfa
 	username, password := selectDatabaseUser(data["databaseUsers"])
 	d.Set("database_username", username)
 	d.Set("database_password", password)
+	d.Set("users", flattenDatabaseUsers(data["databaseUsers"]))
+
+	slug, err := databaseEngineSlug(c, data)
+	if err != nil {
+		tflog.Warn(ctx, "Could not resolve engine slug to build connection URIs", map[string]interface{}{
+			"database_id": d.Id(),
+			"error":       err.Error(),
+		})
+	}
+	if slug != "" {
+		d.Set("engine_slug", slug)
+	}
+
+	regionCode, err := databaseRegionCode(c, data)
+	if err != nil {
+		tflog.Warn(ctx, "Could not resolve region code", map[string]interface{}{
+			"database_id": d.Id(),
+			"error":       err.Error(),
+		})
+	}
+	if regionCode != "" {
+		d.Set("region_code", regionCode)
+	}
+
+	connectionURI, jdbcURL := buildConnectionURIs(slug, params["database_hostname"], params["database_service_port"], username, password)
+	d.Set("connection_uri", connectionURI)
+	d.Set("jdbc_url", jdbcURL)
 
 	return nil
 }
 
 func resourceDatabaseUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Por ahora, solo actualizamos los campos que se pueden modificar
-	// La actualización completa requeriría un endpoint PUT/PATCH en la API
-	return resourceDatabaseRead(ctx, d, m)
+	providerMeta := m.(*meta.Meta)
+	c := providerMeta.Client
+	var diags diag.Diagnostics
+
+	// Los detalles, la protección contra borrado y los accesos de red se
+	// modifican in-place
+	patch := map[string]interface{}{}
+	if d.HasChanges("name", "description") {
+		details := map[string]interface{}{}
+		if d.HasChange("name") {
+			details["name"] = d.Get("name").(string)
+		}
+		if d.HasChange("description") {
+			details["description"] = d.Get("description").(string)
+		}
+		patch["details"] = details
+	}
+	if d.HasChange("deletion_protection") {
+		patch["deletionProtection"] = d.Get("deletion_protection").(bool)
+	}
+	if d.HasChange("ip_whitelist_ids") {
+		patch["ipWhitelistIds"] = d.Get("ip_whitelist_ids").(*schema.Set).List()
+	}
+	if d.HasChange("ssh_key_ids") {
+		patch["sshKeyIds"] = d.Get("ssh_key_ids").(*schema.Set).List()
+	}
+	if d.HasChange("tailscale_config_id") {
+		// null desvincula la base de datos de Tailscale
+		var tailscaleConfigId interface{}
+		if v := d.Get("tailscale_config_id").(string); v != "" {
+			tailscaleConfigId = v
+		}
+		patch["tailscaleConfigId"] = tailscaleConfigId
+	}
+
+	if len(patch) > 0 {
+		if _, err := c.Patch("/api/v1/databases/"+d.Id(), patch); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// El trigger vacío en la creación no rota nada, solo sus cambios posteriores
+	if d.HasChange("password_rotation_trigger") {
+		diags = append(diags, rotateDatabasePassword(ctx, d, c)...)
+		if diags.HasError() {
+			restorePasswordRotationTrigger(d)
+			return diags
+		}
+	}
+
+	if d.HasChange("database_plan") {
+		// Un scale anterior que sigue esperando el pago (p.ej. con
+		// billing_mode = "async") se retoma abajo en lugar de pedirlo otra vez,
+		// que generaría un segundo checkout
+		scalePending, err := databaseAwaitingCheckout(c, d.Id())
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		if scalePending {
+			tflog.Info(ctx, "Database is still waiting for a Stripe checkout, resuming it instead of scaling again", map[string]interface{}{
+				"database_id": d.Id(),
+			})
+		} else {
+			diags = append(diags, scaleDatabase(ctx, d, providerMeta)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	// Terminar la provisión pendiente (p.ej. con billing_mode = "async")
+	if d.HasChange("status") {
+		diags = append(diags, resumeDatabaseProvisioning(ctx, d, providerMeta)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return append(diags, resourceDatabaseRead(ctx, d, m)...)
 }
 
 func resourceDatabaseDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(*client.Client)
+	c := m.(*meta.Meta).Client
+
+	if d.Get("deletion_protection").(bool) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Deletion protection is enabled",
+			Detail:   fmt.Sprintf("Database %s has deletion_protection enabled and cannot be destroyed or replaced. Set deletion_protection = false, run apply, and then retry the destroy.", d.Id()),
+		}}
+	}
 
 	_, err := c.Delete("/api/v1/databases/" + d.Id())
 	if err != nil {
@@ -319,132 +588,1202 @@ func resourceDatabaseDelete(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.FromErr(err)
 	}
 
+	// El backend elimina la base de datos de forma asíncrona
+	if diags := waitForDatabaseDeletion(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); diags.HasError() {
+		return diags
+	}
+
 	d.SetId("")
 	return nil
 }
 
-func waitForDatabaseCredentials(ctx context.Context, c *client.Client, databaseId string) (map[string]interface{}, error) {
-	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"creating", "deploying", "waiting_credentials", "billing_pending"},
-		Target:     []string{"deployed"},
-		MinTimeout: 5 * time.Second,
-		Delay:      5 * time.Second,
-		Timeout:    30 * time.Minute,
-		Refresh: func() (interface{}, string, error) {
-			resp, err := c.Get("/api/v1/databases/" + databaseId)
-			if err != nil {
-				return nil, "", err
-			}
+// resourceDatabaseImport acepta un ID numérico o
+// organization_slug/namespace_slug/name
+func resourceDatabaseImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*meta.Meta).Client
 
-			data, ok := resp.Data.(map[string]interface{})
-			if !ok {
-				return nil, "", fmt.Errorf("unexpected database response format")
-			}
+	if err := importScopedResource(c, d, "/api/v1/databases", "database", "database"); err != nil {
+		return nil, err
+	}
 
-			status, _ := data["status"].(string)
-			if url := extractStripeCheckoutURL(data); url != "" {
-				tflog.Warn(ctx, "Waiting for user to complete Stripe checkout", map[string]interface{}{
-					"stripe_checkout_url": url,
-					"database_id":         databaseId,
-				})
-			}
-			if credentialsAreReady(data) {
-				return data, status, nil
-			}
+	// resourceDatabaseRead rellena el resto del estado tras el import
+	return []*schema.ResourceData{d}, nil
+}
 
-			return data, "waiting_credentials", nil
-		},
+// setDatabaseIdentity guarda los atributos que identifican dónde y cómo se
+// provisionó la base de datos, incluido su plan actual
+func setDatabaseIdentity(ctx context.Context, d *schema.ResourceData, c *client.Client, data map[string]interface{}) error {
+	if v, ok := data["organizationSlug"].(string); ok {
+		if err := d.Set("organization_slug", v); err != nil {
+			return err
+		}
 	}
-
-	result, err := stateConf.WaitForStateContext(ctx)
-	if err != nil {
-		return nil, err
+	if v, ok := data["namespaceSlug"].(string); ok {
+		if err := d.Set("namespace_slug", v); err != nil {
+			return err
+		}
 	}
-
-	data, ok := result.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected result type %T when waiting for database %s", result, databaseId)
+	if err := d.Set("engine_id", idToString(data["engineId"])); err != nil {
+		return err
+	}
+	if err := d.Set("region_id", idToString(data["regionId"])); err != nil {
+		return err
 	}
+	if v, ok := data["databasePlanDetails"]; ok && v != nil {
+		items := databasePlanItems(ctx, c, data)
+		if err := d.Set("database_plan", flattenDatabasePlan(v, items)); err != nil {
+			return err
+		}
 
-	return data, nil
+		sizing := sizingFromPlan(mapDatabasePlanItems(v), items)
+		d.Set("cpu_cores", sizing["cpu_cores"])
+		d.Set("memory_gib", sizing["memory_gib"])
+		d.Set("storage_gib", sizing["storage_gib"])
+		d.Set("bandwidth_mbps", int(math.Round(sizing["bandwidth_mbps"])))
+	}
+	return nil
 }
 
-func credentialsAreReady(data map[string]interface{}) bool {
-	params := mapDatabaseParams(data["databaseParams"])
-	if params["database_hostname"] == "" || params["database_service_port"] == "" {
-		return false
-	}
+// databasePlanItems devuelve la metadata de cada billable item del plan, de la
+// respuesta o, si no incluye el nombre o el tamaño, de la create metadata
+func databasePlanItems(ctx context.Context, c *client.Client, data map[string]interface{}) map[string]billableItemMetadata {
+	items := map[string]billableItemMetadata{}
+	missing := false
 
-	username, password := selectDatabaseUser(data["databaseUsers"])
-	return username != "" && password != ""
-}
+	details, _ := data["databasePlanDetails"].(map[string]interface{})
+	planItems, _ := details["databasePlanBI"].([]interface{})
+	for _, i := range planItems {
+		itemMap, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
 
-func mapDatabaseParams(raw interface{}) map[string]string {
-	result := map[string]string{
-		"database_hostname":     "",
-		"database_service_port": "",
+		id := idToString(itemMap["billableItemId"])
+		billableItem, _ := itemMap["billableItem"].(map[string]interface{})
+		item := parseBillableItemMetadata(id, billableItem)
+		items[id] = item
+
+		_, sized := billableItemUnit(item)
+		missing = missing || item.Name == "" || (item.Kind() != "" && !sized)
 	}
 
-	params, ok := raw.([]interface{})
-	if !ok {
-		return result
+	if !missing {
+		return items
 	}
 
-	for _, p := range params {
-		paramMap, ok := p.(map[string]interface{})
-		if !ok {
-			continue
+	md, err := getDatabaseCreateMetadata(c, idToString(data["engineId"]), idToString(data["regionId"]))
+	if err != nil {
+		tflog.Warn(ctx, "Could not fetch create metadata to describe billable items", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return items
+	}
+	for id, item := range items {
+		if known, ok := md.billableItem(id); ok && (item.Name == "" || known.Name == item.Name) {
+			items[id] = known
 		}
+	}
+	return items
+}
 
-		key, _ := paramMap["key"].(string)
-		value, _ := paramMap["value"].(string)
+func resourceDatabaseCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	c := m.(*meta.Meta).Client
 
-		if key == "" {
-			continue
-		}
-		result[key] = value
+	if err := resolveEngineSlug(d, c); err != nil {
+		return err
 	}
 
-	return result
-}
+	if err := resolveRegionCode(d, c); err != nil {
+		return err
+	}
 
-func selectDatabaseUser(raw interface{}) (string, string) {
-	users, ok := raw.([]interface{})
-	if !ok || len(users) == 0 {
-		return "", ""
+	if err := validateEngineAndRegion(d, c); err != nil {
+		return err
 	}
 
-	var fallbackUsername, fallbackPassword string
-	for _, u := range users {
-		userMap, ok := u.(map[string]interface{})
-		if !ok {
-			continue
+	if d.Id() == "" || d.HasChanges("database_plan", "cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps") {
+		if err := customizeDatabasePlan(d, c); err != nil {
+			return err
 		}
+	}
 
-		username, _ := userMap["username"].(string)
-		password, _ := userMap["password"].(string)
-		role, _ := userMap["role"].(string)
+	if d.Id() == "" {
+		return nil
+	}
 
-		if username == "" || password == "" {
-			continue
+	if d.HasChange("password_rotation_trigger") {
+		if err := d.SetNewComputed("database_password"); err != nil {
+			return err
 		}
-
-		if role == "root" || username == "root" {
-			return username, password
+		if err := d.SetNewComputed("users"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("connection_uri"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("jdbc_url"); err != nil {
+			return err
 		}
+	}
 
-		if fallbackUsername == "" {
-			fallbackUsername = username
-			fallbackPassword = password
+	// La dirección en la tailnet cambia al cambiar de configuración de Tailscale
+	if d.HasChange("tailscale_config_id") {
+		if err := d.SetNewComputed("tailscale_hostname"); err != nil {
+			return err
+		}
+		if err := d.SetNewComputed("tailscale_ip"); err != nil {
+			return err
 		}
 	}
 
-	return fallbackUsername, fallbackPassword
+	// Si la base de datos sigue pendiente de provisión, planificar un update
+	// para que el siguiente apply termine de esperarla
+	status := d.Get("status").(string)
+	provisioningPending := d.Get("provisioning_pending").(bool)
+	if !isPendingStatus(status) && !provisioningPending {
+		return nil
+	}
+
+	if err := d.SetNewComputed("status"); err != nil {
+		return err
+	}
+	if err := d.SetNewComputed("provisioning_pending"); err != nil {
+		return err
+	}
+
+	// Una provisión interrumpida que el backend dio por fallida no se puede
+	// retomar, hay que reemplazar la base de datos
+	if provisioningPending && isFailedStatus(status) {
+		return d.ForceNew("status")
+	}
+	return nil
 }
 
-func extractStripeCheckoutURL(data map[string]interface{}) string {
-	if sessionRaw, ok := data["stripeCheckoutSession"]; ok && sessionRaw != nil {
-		if sessionMap, ok := sessionRaw.(map[string]interface{}); ok {
+// resolveEngineSlug fija engine_id a partir de engine_slug y engine_version.
+// Una base de datos existente conserva su engine mientras los siga cumpliendo,
+// para que una versión nueva no fuerce el reemplazo
+func resolveEngineSlug(d *schema.ResourceDiff, c *client.Client) error {
+	rawConfig := d.GetRawConfig()
+	if !rawConfig.GetAttr("engine_id").IsNull() {
+		return nil
+	}
+	if !d.NewValueKnown("engine_slug") || !d.NewValueKnown("engine_version") {
+		return d.SetNewComputed("engine_id")
+	}
+	if d.Id() != "" && !d.HasChanges("engine_slug", "engine_version") {
+		return nil
+	}
+
+	slug := d.Get("engine_slug").(string)
+	constraint := d.Get("engine_version").(string)
+	if slug == "" {
+		return nil
+	}
+
+	engines, err := listEngines(c)
+	if err != nil {
+		return fmt.Errorf("error listing engines to resolve engine_slug: %w", err)
+	}
+
+	if d.Id() != "" {
+		oldId, _ := d.GetChange("engine_id")
+		for _, e := range engines {
+			if e.ID == oldId.(string) && engineSatisfies(e, slug, constraint) {
+				return nil
+			}
+		}
+	}
+
+	engine, err := resolveEngine(engines, slug, constraint)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("engine_id", engine.ID)
+}
+
+// resolveRegionCode fija region_id a partir de region_code
+func resolveRegionCode(d *schema.ResourceDiff, c *client.Client) error {
+	if !d.GetRawConfig().GetAttr("region_id").IsNull() {
+		return nil
+	}
+	if !d.NewValueKnown("region_code") {
+		return d.SetNewComputed("region_id")
+	}
+	if d.Id() != "" && !d.HasChange("region_code") {
+		return nil
+	}
+
+	code := d.Get("region_code").(string)
+	if code == "" {
+		return nil
+	}
+
+	regions, err := listRegions(c)
+	if err != nil {
+		return fmt.Errorf("error listing regions to resolve region_code: %w", err)
+	}
+
+	region, err := findRegionByCode(regions, code)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("region_id", region.ID)
+}
+
+// validateEngineAndRegion comprueba engine_id y region_id contra el catálogo,
+// para que un valor inactivo o desconocido falle en el plan y no en el apply
+func validateEngineAndRegion(d *schema.ResourceDiff, c *client.Client) error {
+	var errs []error
+
+	if d.NewValueKnown("engine_id") && (d.Id() == "" || d.HasChange("engine_id")) {
+		engines, err := listEngines(c)
+		if err != nil {
+			return fmt.Errorf("error listing engines to validate engine_id: %w", err)
+		}
+		if err := checkEngineID(engines, d.Get("engine_id").(string)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if d.NewValueKnown("region_id") && (d.Id() == "" || d.HasChange("region_id")) {
+		regions, err := listRegions(c)
+		if err != nil {
+			return fmt.Errorf("error listing regions to validate region_id: %w", err)
+		}
+		if err := checkRegionID(regions, d.Get("region_id").(string)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// customizeDatabasePlan resuelve los nombres de los billable items y valida
+// database_plan contra la create metadata del engine y la región elegidos
+func customizeDatabasePlan(d *schema.ResourceDiff, c *client.Client) error {
+	rawConfig := d.GetRawConfig()
+	sized := !rawConfig.GetAttr("cpu_cores").IsNull()
+	if d.Id() == "" && !sized {
+		if plan := rawConfig.GetAttr("database_plan"); plan.IsKnown() && !plan.IsNull() && plan.LengthInt() == 0 {
+			return fmt.Errorf("database_plan: either a database_plan block or cpu_cores, memory_gib, storage_gib and bandwidth_mbps are required")
+		}
+	}
+
+	if !d.NewValueKnown("engine_id") || !d.NewValueKnown("region_id") {
+		return nil
+	}
+	sizingKnown := true
+	for attr := range sizingAttributes {
+		sizingKnown = sizingKnown && d.NewValueKnown(attr)
+	}
+	if (sized && !sizingKnown) || (!sized && !d.NewValueKnown("database_plan")) {
+		return nil
+	}
+
+	md, err := getDatabaseCreateMetadata(c, d.Get("engine_id").(string), d.Get("region_id").(string))
+	if err != nil {
+		return fmt.Errorf("error fetching create metadata to validate database_plan: %w", err)
+	}
+
+	if sized {
+		if err := applyDatabaseSizing(d, md); err != nil {
+			return err
+		}
+	} else if err := resolveBillableItemNames(d, md); err != nil {
+		return err
+	}
+	return validateDatabasePlan(d, md)
+}
+
+// applyDatabaseSizing calcula database_plan a partir de los atributos de
+// tamaño. Una base de datos existente conserva su plan mientras estos no
+// cambien
+func applyDatabaseSizing(d *schema.ResourceDiff, md *databaseCreateMetadata) error {
+	if d.Id() != "" && !d.HasChanges("cpu_cores", "memory_gib", "storage_gib", "bandwidth_mbps") {
+		return nil
+	}
+
+	plan, err := buildSizedPlan(md, map[string]float64{
+		"cpu_cores":      d.Get("cpu_cores").(float64),
+		"memory_gib":     d.Get("memory_gib").(float64),
+		"storage_gib":    d.Get("storage_gib").(float64),
+		"bandwidth_mbps": float64(d.Get("bandwidth_mbps").(int)),
+	})
+	if err != nil {
+		return err
+	}
+	return d.SetNew("database_plan", plan)
+}
+
+// resolveBillableItemNames completa billable_item_id y name de cada item, para
+// que el plan tenga ambos y coincida con lo que guarda Read
+func resolveBillableItemNames(d *schema.ResourceDiff, md *databaseCreateMetadata) error {
+	const path = "database_plan.0.billable_items"
+
+	plans := d.Get("database_plan").([]interface{})
+	if len(plans) == 0 || plans[0] == nil {
+		return nil
+	}
+
+	var errs []error
+	changed := false
+	items := plans[0].(map[string]interface{})["billable_items"].(*schema.Set).List()
+	resolved := make([]interface{}, 0, len(items))
+	for _, raw := range items {
+		item := raw.(map[string]interface{})
+		id, _ := item["billable_item_id"].(string)
+		name, _ := item["name"].(string)
+
+		switch {
+		case id == "" && name == "":
+			errs = append(errs, fmt.Errorf("%s: each billable item must set billable_item_id or name", path))
+		case id == "":
+			known, ok := md.billableItemByName(name)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown billable item name %q for this engine and region, valid items are: %s", path, name, md.describeBillableItems()))
+				continue
+			}
+			id, changed = known.ID, true
+		case name == "":
+			if known, ok := md.billableItem(id); ok {
+				name, changed = known.Name, true
+			}
+		default:
+			if known, ok := md.billableItem(id); ok && known.Name != name {
+				errs = append(errs, fmt.Errorf("%s: billable item %q is %q, not %q", path, id, known.Name, name))
+			}
+		}
+
+		resolved = append(resolved, map[string]interface{}{
+			"billable_item_id": id,
+			"name":             name,
+			"quantity":         item["quantity"],
+		})
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if !changed {
+		return nil
+	}
+
+	return d.SetNew("database_plan", []interface{}{
+		map[string]interface{}{
+			"billable_items": resolved,
+		},
+	})
+}
+
+// validateDatabasePlan comprueba database_plan contra la create metadata del
+// engine y la región elegidos, para que un plan inválido falle antes del POST
+func validateDatabasePlan(d *schema.ResourceDiff, md *databaseCreateMetadata) error {
+	if err := checkDatabasePlanItems(md, flattenPlanQuantities(d.Get("database_plan"))); err != nil {
+		return err
+	}
+
+	if d.Id() != "" {
+		return validateDatabasePlanChange(d, md)
+	}
+	return nil
+}
+
+// checkDatabasePlanItems comprueba que los billable items del plan existen,
+// están dentro de su rango e incluyen todos los obligatorios
+func checkDatabasePlanItems(md *databaseCreateMetadata, items map[string]int) error {
+	const path = "database_plan.0.billable_items"
+
+	ids := make([]string, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		quantity := items[id]
+		item, ok := md.billableItem(id)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown billable item %q for this engine and region, valid items are: %s", path, id, md.describeBillableItems()))
+			continue
+		}
+		if quantity < item.Min || (item.Max > 0 && quantity > item.Max) {
+			errs = append(errs, fmt.Errorf("%s: quantity %d for billable item %q (%s) is out of range, expected %d-%d", path, quantity, id, item.Name, item.Min, item.Max))
+		}
+	}
+
+	for _, item := range md.BillableItems {
+		if _, ok := items[item.ID]; item.Required && !ok {
+			errs = append(errs, fmt.Errorf("%s: missing required billable item %q (%s)", path, item.ID, item.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateDatabasePlanChange rechaza los cambios de plan que el backend no
+// puede aplicar in-place: añadir o quitar items, escalar items que no son
+// recursos y reducir el almacenamiento
+func validateDatabasePlanChange(d *schema.ResourceDiff, md *databaseCreateMetadata) error {
+	oldPlan, newPlan := d.GetChange("database_plan")
+	oldItems := flattenPlanQuantities(oldPlan)
+	newItems := flattenPlanQuantities(newPlan)
+
+	for id := range oldItems {
+		if _, ok := newItems[id]; !ok {
+			return fmt.Errorf("billable item %s cannot be removed from database_plan in place, recreate the database instead", id)
+		}
+	}
+	for id := range newItems {
+		if _, ok := oldItems[id]; !ok {
+			return fmt.Errorf("billable item %s cannot be added to database_plan in place, recreate the database instead", id)
+		}
+	}
+
+	for id, newQuantity := range newItems {
+		oldQuantity := oldItems[id]
+		if newQuantity == oldQuantity {
+			continue
+		}
+
+		item, _ := md.billableItem(id)
+		switch item.Kind() {
+		case "":
+			return fmt.Errorf("billable item %s (%s) cannot be scaled in place", id, item.Name)
+		case billableItemKindStorage:
+			if newQuantity < oldQuantity {
+				return fmt.Errorf("billable item %s (%s) cannot be shrunk from %d to %d, storage can only grow", id, item.Name, oldQuantity, newQuantity)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rotateDatabasePassword rota la contraseña del usuario de database_username y
+// espera a que la API devuelva las nuevas credenciales
+func rotateDatabasePassword(ctx context.Context, d *schema.ResourceData, c *client.Client) diag.Diagnostics {
+	databaseId := d.Id()
+	username := d.Get("database_username").(string)
+	if username == "" {
+		return diag.Errorf("cannot rotate the password of database %s: no database user is known yet", databaseId)
+	}
+	oldPassword, _ := d.GetChange("database_password")
+
+	if err := rotateUserPassword(ctx, c, databaseId, username, oldPassword.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Info(ctx, "Database password rotated", map[string]interface{}{
+		"database_id": databaseId,
+		"username":    username,
+	})
+	return nil
+}
+
+// restorePasswordRotationTrigger deja en el estado el trigger anterior cuando
+// la rotación falla, para que el siguiente apply vuelva a intentarla
+func restorePasswordRotationTrigger(d *schema.ResourceData) {
+	oldTrigger, _ := d.GetChange("password_rotation_trigger")
+	d.Set("password_rotation_trigger", oldTrigger)
+}
+
+// rotateUserPassword pide a la API una contraseña nueva para el usuario y
+// espera a que aparezca en los usuarios de la base de datos
+func rotateUserPassword(ctx context.Context, c *client.Client, databaseId, username, oldPassword string, timeout time.Duration) error {
+	path := fmt.Sprintf("/api/v1/databases/%s/users/%s/rotate-password", databaseId, url.PathEscape(username))
+	if _, err := c.Post(path, nil); err != nil {
+		return err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"rotating"},
+		Target:     []string{"rotated"},
+		MinTimeout: 5 * time.Second,
+		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get("/api/v1/databases/" + databaseId)
+			if err != nil {
+				return nil, "", err
+			}
+
+			data, ok := resp.Data.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("unexpected database response format")
+			}
+
+			user := findDatabaseUser(data["databaseUsers"], username)
+			password, _ := user["password"].(string)
+			if password == "" || password == oldPassword {
+				return data, "rotating", nil
+			}
+			return data, "rotated", nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the new password of %s in database %s: %w", username, databaseId, err)
+	}
+	return nil
+}
+
+// scaleDatabase aplica las nuevas cantidades de database_plan y espera a que el
+// backend dé la base de datos por desplegada con el nuevo plan
+func scaleDatabase(ctx context.Context, d *schema.ResourceData, providerMeta *meta.Meta) diag.Diagnostics {
+	c := providerMeta.Client
+	var diags diag.Diagnostics
+	databaseId := d.Id()
+
+	resp, err := c.Post("/api/v1/databases/"+databaseId+"/scale", map[string]interface{}{
+		"databasePlanDetails": map[string]interface{}{
+			"databasePlanBI": expandDatabasePlanItems(d.Get("database_plan")),
+		},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// El upgrade puede requerir un nuevo Stripe checkout
+	if data, ok := resp.Data.(map[string]interface{}); ok {
+		if url := extractStripeCheckoutURL(data); url != "" {
+			wait, checkoutDiags := handleStripeCheckout(ctx, d, providerMeta, data)
+			diags = append(diags, checkoutDiags...)
+			if !wait {
+				return diags
+			}
+		}
+	}
+
+	if err := waitForDatabasePlan(ctx, c, databaseId, flattenPlanQuantities(d.Get("database_plan")), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// databaseAwaitingCheckout indica si la base de datos está escalando o
+// provisionándose con un Stripe checkout todavía pendiente
+func databaseAwaitingCheckout(c *client.Client, databaseId string) (bool, error) {
+	resp, err := c.Get("/api/v1/databases/" + databaseId)
+	if err != nil {
+		return false, err
+	}
+
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("unexpected database response format")
+	}
+
+	status, _ := data["status"].(string)
+	return (status == "scaling" || status == "billing_pending") && extractStripeCheckoutURL(data) != "", nil
+}
+
+// resumeDatabaseProvisioning espera a una base de datos que un apply anterior
+// dejó pendiente, respetando el billing mode configurado
+func resumeDatabaseProvisioning(ctx context.Context, d *schema.ResourceData, providerMeta *meta.Meta) diag.Diagnostics {
+	c := providerMeta.Client
+	resp, err := c.Get("/api/v1/databases/" + d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		return diag.Errorf("unexpected database response format")
+	}
+
+	status, _ := data["status"].(string)
+	if status == "deployed" && credentialsAreReady(data) {
+		d.Set("provisioning_pending", false)
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	if url := extractStripeCheckoutURL(data); url != "" {
+		wait, checkoutDiags := handleStripeCheckout(ctx, d, providerMeta, data)
+		diags = append(diags, checkoutDiags...)
+		if !wait {
+			return diags
+		}
+	}
+
+	progress := newProvisioningProgress(d.Id())
+	if _, err := waitForDatabaseCredentials(ctx, c, d.Id(), d.Timeout(schema.TimeoutUpdate), progress); err != nil {
+		diags = append(diags, progress.slowDiagnostics()...)
+		return append(diags, handleProvisioningError(ctx, d, c, err)...)
+	}
+	d.Set("provisioning_pending", false)
+	diags = append(diags, progress.slowDiagnostics()...)
+
+	return diags
+}
+
+// handleStripeCheckout avisa a los sinks configurados de un Stripe checkout
+// pendiente y devuelve, según el billing mode, si la provisión tiene que seguir
+// esperándolo
+func handleStripeCheckout(ctx context.Context, d *schema.ResourceData, providerMeta *meta.Meta, data map[string]interface{}) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	databaseId := d.Id()
+	url := extractStripeCheckoutURL(data)
+	if err := d.Set("stripe_checkout_url", url); err != nil {
+		return false, diag.FromErr(err)
+	}
+
+	billingMode := resolveBillingMode(d, providerMeta)
+	if providerMeta.Notifier != nil {
+		errs := providerMeta.Notifier.NotifyPaymentRequired(ctx, notify.PaymentRequired{
+			DatabaseID:   databaseId,
+			DatabaseName: d.Get("name").(string),
+			CheckoutURL:  url,
+			ExpiresAt:    extractStripeCheckoutExpiry(data),
+			BillingMode:  billingMode,
+		})
+		for _, err := range errs {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Payment notification failed",
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	switch billingMode {
+	case meta.BillingModeFailFast:
+		return false, append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Payment required",
+			Detail:   fmt.Sprintf("Database %s requires a Stripe checkout and billing_mode is %q. Complete the checkout and run apply again: %s", databaseId, meta.BillingModeFailFast, url),
+		})
+	case meta.BillingModeAsync:
+		tflog.Info(ctx, "Database provisioning deferred until Stripe checkout completes", map[string]interface{}{
+			"stripe_checkout_url": url,
+			"database_id":         databaseId,
+		})
+		return false, append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Payment required",
+			Detail:   fmt.Sprintf("Open the checkout URL to complete billing, provisioning will finish on the next apply: %s", url),
+		})
+	}
+
+	tflog.Info(ctx, "Database provisioning blocked until Stripe checkout completes", map[string]interface{}{
+		"stripe_checkout_url": url,
+		"database_id":         databaseId,
+	})
+	return true, append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Payment required",
+		Detail:   fmt.Sprintf("Open the checkout URL to complete billing and resume provisioning: %s", url),
+	})
+}
+
+func resolveBillingMode(d *schema.ResourceData, providerMeta *meta.Meta) string {
+	if v, ok := d.GetOk("billing_mode"); ok {
+		return v.(string)
+	}
+	if providerMeta.BillingMode != "" {
+		return providerMeta.BillingMode
+	}
+	return meta.BillingModeWait
+}
+
+func isFailedStatus(status string) bool {
+	for _, s := range databaseFailedStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isResumableWaitError indica si la espera se cortó por timeout o cancelación,
+// casos en los que la base de datos puede seguir provisionándose
+func isResumableWaitError(ctx context.Context, err error) bool {
+	var timeoutErr *resource.TimeoutError
+	return errors.As(err, &timeoutErr) || ctx.Err() != nil
+}
+
+func isPendingStatus(status string) bool {
+	for _, s := range databasePendingStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForDatabaseDeletion consulta la base de datos hasta que la API devuelve
+// 404, para que un destroy seguido de un create con el mismo nombre no compita
+// con el borrado
+func waitForDatabaseDeletion(ctx context.Context, c *client.Client, databaseId string, timeout time.Duration) diag.Diagnostics {
+	var lastData map[string]interface{}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    append(append([]string{"deleting", "deployed"}, databasePendingStatuses...), databaseFailedStatuses...),
+		Target:     []string{"deleted"},
+		MinTimeout: 5 * time.Second,
+		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get("/api/v1/databases/" + databaseId)
+			if err != nil {
+				if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+					return struct{}{}, "deleted", nil
+				}
+				return nil, "", err
+			}
+
+			data, ok := resp.Data.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("unexpected database response format")
+			}
+			lastData = data
+
+			status, _ := data["status"].(string)
+			if status == "delete_failed" {
+				return nil, "", fmt.Errorf("backend reported a failed deletion: %s", databaseFailureReason(data))
+			}
+			return data, status, nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		detail := err.Error()
+		if lastData != nil {
+			status, _ := lastData["status"].(string)
+			detail = fmt.Sprintf("%s\n\nLast reported status: %q. Reason: %s", detail, status, databaseFailureReason(lastData))
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Database %s was not deleted", databaseId),
+			Detail:   detail + "\n\nThe deletion may be stuck on the backend, check the database in the filess.io console or contact support before retrying.",
+		}}
+	}
+
+	return nil
+}
+
+// databaseFailedError es el error que devuelven las esperas cuando el backend
+// pasa la base de datos a un estado de fallo definitivo
+type databaseFailedError struct {
+	DatabaseID string
+	Status     string
+	Reason     string
+	Events     []string
+}
+
+func newDatabaseFailedError(databaseId string, data map[string]interface{}) *databaseFailedError {
+	status, _ := data["status"].(string)
+	return &databaseFailedError{
+		DatabaseID: databaseId,
+		Status:     status,
+		Reason:     databaseFailureReason(data),
+		Events:     databaseEvents(data),
+	}
+}
+
+func (e *databaseFailedError) Error() string {
+	msg := fmt.Sprintf("database %s reached status %q: %s", e.DatabaseID, e.Status, e.Reason)
+	if len(e.Events) > 0 {
+		msg += "\n\nRecent events:\n  - " + strings.Join(e.Events, "\n  - ")
+	}
+	return msg
+}
+
+// handleProvisioningError convierte el error de una espera en diagnósticos,
+// borrando antes la base de datos rota si cleanup_on_failure está activado
+func handleProvisioningError(ctx context.Context, d *schema.ResourceData, c *client.Client, err error) diag.Diagnostics {
+	var failedErr *databaseFailedError
+	if !errors.As(err, &failedErr) {
+		return diag.FromErr(err)
+	}
+
+	diags := diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Database provisioning failed",
+		Detail:   failedErr.Error(),
+	}}
+
+	// deletion_protection prevalece sobre cleanup_on_failure
+	if !d.Get("cleanup_on_failure").(bool) || d.Get("deletion_protection").(bool) {
+		return diags
+	}
+
+	tflog.Info(ctx, "Deleting database after failed provisioning", map[string]interface{}{
+		"database_id": d.Id(),
+		"status":      failedErr.Status,
+	})
+	if _, err := c.Delete("/api/v1/databases/" + d.Id()); err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Cleanup of failed database failed",
+			Detail:   fmt.Sprintf("cleanup_on_failure is enabled but deleting database %s failed: %s", d.Id(), err),
+		})
+	}
+	if deleteDiags := waitForDatabaseDeletion(ctx, c, d.Id(), d.Timeout(schema.TimeoutDelete)); deleteDiags.HasError() {
+		return append(diags, deleteDiags...)
+	}
+
+	d.SetId("")
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Failed database deleted",
+		Detail:   fmt.Sprintf("Database %s was deleted because cleanup_on_failure is enabled.", failedErr.DatabaseID),
+	})
+}
+
+// databaseEvents formatea los eventos recientes devueltos por el backend
+func databaseEvents(data map[string]interface{}) []string {
+	raw, ok := data["events"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	events := make([]string, 0, len(raw))
+	for _, e := range raw {
+		event, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		message, _ := event["message"].(string)
+		if message == "" {
+			continue
+		}
+		if createdAt, _ := event["createdAt"].(string); createdAt != "" {
+			message = createdAt + ": " + message
+		}
+		events = append(events, message)
+	}
+
+	return events
+}
+
+// databaseFailureReason extrae el motivo del fallo informado por el backend
+func databaseFailureReason(data map[string]interface{}) string {
+	if reason, _ := data["failureReason"].(string); reason != "" {
+		return reason
+	}
+	return "not reported"
+}
+
+func waitForDatabaseCredentials(ctx context.Context, c *client.Client, databaseId string, timeout time.Duration, progress *provisioningProgress) (map[string]interface{}, error) {
+	stateConf := &resource.StateChangeConf{
+		Pending:    databasePendingStatuses,
+		Target:     []string{"deployed"},
+		MinTimeout: 5 * time.Second,
+		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get("/api/v1/databases/" + databaseId)
+			if err != nil {
+				return nil, "", err
+			}
+
+			data, ok := resp.Data.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("unexpected database response format")
+			}
+
+			status, _ := data["status"].(string)
+			if isFailedStatus(status) {
+				return nil, "", newDatabaseFailedError(databaseId, data)
+			}
+			url := extractStripeCheckoutURL(data)
+			if url != "" {
+				tflog.Warn(ctx, "Waiting for user to complete Stripe checkout", map[string]interface{}{
+					"stripe_checkout_url": url,
+					"database_id":         databaseId,
+				})
+			}
+
+			// Las fases se registran con el estado del backend; waiting_credentials
+			// solo mantiene la espera hasta que las credenciales estén listas
+			progress.observe(ctx, status, url)
+			state := "waiting_credentials"
+			if credentialsAreReady(data) {
+				state = status
+			}
+
+			return data, state, nil
+		},
+	}
+
+	result, err := stateConf.WaitForStateContext(ctx)
+	progress.finish(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %T when waiting for database %s", result, databaseId)
+	}
+
+	return data, nil
+}
+
+func waitForDatabasePlan(ctx context.Context, c *client.Client, databaseId string, expected map[string]int, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    databasePendingStatuses,
+		Target:     []string{"deployed"},
+		MinTimeout: 5 * time.Second,
+		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get("/api/v1/databases/" + databaseId)
+			if err != nil {
+				return nil, "", err
+			}
+
+			data, ok := resp.Data.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("unexpected database response format")
+			}
+
+			status, _ := data["status"].(string)
+			if isFailedStatus(status) {
+				return nil, "", newDatabaseFailedError(databaseId, data)
+			}
+			if status == "deployed" && !planQuantitiesMatch(mapDatabasePlanItems(data["databasePlanDetails"]), expected) {
+				return data, "scaling", nil
+			}
+
+			return data, status, nil
+		},
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func expandDatabasePlanItems(raw interface{}) []map[string]interface{} {
+	plans, ok := raw.([]interface{})
+	if !ok || len(plans) == 0 || plans[0] == nil {
+		return []map[string]interface{}{}
+	}
+
+	plan := plans[0].(map[string]interface{})
+	billableItems := plan["billable_items"].(*schema.Set).List()
+
+	databasePlanBI := make([]map[string]interface{}, len(billableItems))
+	for i, item := range billableItems {
+		itemMap := item.(map[string]interface{})
+		databasePlanBI[i] = map[string]interface{}{
+			"billableItemId": itemMap["billable_item_id"].(string),
+			"quantity":       itemMap["quantity"].(int),
+		}
+	}
+
+	return databasePlanBI
+}
+
+// flattenPlanQuantities indexa las cantidades del database_plan por billable item
+func flattenPlanQuantities(raw interface{}) map[string]int {
+	result := map[string]int{}
+	for _, item := range expandDatabasePlanItems(raw) {
+		result[item["billableItemId"].(string)] = item["quantity"].(int)
+	}
+	return result
+}
+
+// mapDatabasePlanItems indexa las cantidades del plan devuelto por la API
+func mapDatabasePlanItems(raw interface{}) map[string]int {
+	result := map[string]int{}
+
+	details, ok := raw.(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	items, _ := details["databasePlanBI"].([]interface{})
+	for _, i := range items {
+		itemMap, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		quantity, _ := itemMap["quantity"].(float64)
+		result[idToString(itemMap["billableItemId"])] = int(quantity)
+	}
+
+	return result
+}
+
+// flattenIDList acepta tanto listas de IDs como listas de objetos con "id"
+func flattenIDList(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			item = obj["id"]
+		}
+		if item == nil {
+			continue
+		}
+		ids = append(ids, idToString(item))
+	}
+
+	return ids
+}
+
+func flattenDatabasePlan(raw interface{}, items map[string]billableItemMetadata) []interface{} {
+	quantities := mapDatabasePlanItems(raw)
+	if len(quantities) == 0 {
+		return []interface{}{}
+	}
+
+	billableItems := make([]interface{}, 0, len(quantities))
+	for id, quantity := range quantities {
+		billableItems = append(billableItems, map[string]interface{}{
+			"billable_item_id": id,
+			"name":             items[id].Name,
+			"quantity":         quantity,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"billable_items": billableItems,
+		},
+	}
+}
+
+func planQuantitiesMatch(actual, expected map[string]int) bool {
+	for id, quantity := range expected {
+		if actual[id] != quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func credentialsAreReady(data map[string]interface{}) bool {
+	params := mapDatabaseParams(data["databaseParams"])
+	if params["database_hostname"] == "" || params["database_service_port"] == "" {
+		return false
+	}
+
+	username, password := selectDatabaseUser(data["databaseUsers"])
+	return username != "" && password != ""
+}
+
+func mapDatabaseParams(raw interface{}) map[string]string {
+	result := map[string]string{
+		"database_hostname":     "",
+		"database_service_port": "",
+	}
+
+	params, ok := raw.([]interface{})
+	if !ok {
+		return result
+	}
+
+	for _, p := range params {
+		paramMap, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		key, _ := paramMap["key"].(string)
+		value, _ := paramMap["value"].(string)
+
+		if key == "" {
+			continue
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+func selectDatabaseUser(raw interface{}) (string, string) {
+	users, ok := raw.([]interface{})
+	if !ok || len(users) == 0 {
+		return "", ""
+	}
+
+	var fallbackUsername, fallbackPassword string
+	for _, u := range users {
+		userMap, ok := u.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		username, _ := userMap["username"].(string)
+		password, _ := userMap["password"].(string)
+		role, _ := userMap["role"].(string)
+
+		if username == "" || password == "" {
+			continue
+		}
+
+		if role == "root" || username == "root" {
+			return username, password
+		}
+
+		if fallbackUsername == "" {
+			fallbackUsername = username
+			fallbackPassword = password
+		}
+	}
+
+	return fallbackUsername, fallbackPassword
+}
+
+func flattenDatabaseUsers(raw interface{}) []interface{} {
+	users, ok := raw.([]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+
+	result := make([]interface{}, 0, len(users))
+	for _, u := range users {
+		userMap, ok := u.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		username, _ := userMap["username"].(string)
+		if username == "" {
+			continue
+		}
+		role, _ := userMap["role"].(string)
+		password, _ := userMap["password"].(string)
+
+		result = append(result, map[string]interface{}{
+			"username": username,
+			"role":     role,
+			"password": password,
+		})
+	}
+
+	return result
+}
+
+// findDatabaseUser devuelve el usuario con ese nombre, o nil si no existe
+func findDatabaseUser(raw interface{}, username string) map[string]interface{} {
+	users, _ := raw.([]interface{})
+	for _, u := range users {
+		userMap, ok := u.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := userMap["username"].(string); name == username {
+			return userMap
+		}
+	}
+	return nil
+}
+
+func extractStripeCheckoutURL(data map[string]interface{}) string {
+	if sessionRaw, ok := data["stripeCheckoutSession"]; ok && sessionRaw != nil {
+		if sessionMap, ok := sessionRaw.(map[string]interface{}); ok {
 			if url, _ := sessionMap["url"].(string); url != "" {
 				return url
 			}
@@ -452,3 +1791,19 @@ func extractStripeCheckoutURL(data map[string]interface{}) string {
 	}
 	return ""
 }
+
+// extractStripeCheckoutExpiry devuelve la expiración del checkout en RFC 3339
+func extractStripeCheckoutExpiry(data map[string]interface{}) string {
+	sessionMap, ok := data["stripeCheckoutSession"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	switch v := sessionMap["expiresAt"].(type) {
+	case float64:
+		return time.Unix(int64(v), 0).UTC().Format(time.RFC3339)
+	case string:
+		return v
+	}
+	return ""
+}
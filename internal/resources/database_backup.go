@@ -0,0 +1,332 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Estados en los que el snapshot todavía se está generando
+var backupPendingStatuses = []string{"pending", "queued", "running", "in_progress"}
+
+func ResourceDatabaseBackup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseBackupCreate,
+		ReadContext:   resourceDatabaseBackupRead,
+		UpdateContext: resourceDatabaseBackupUpdate,
+		DeleteContext: resourceDatabaseBackupDelete,
+		CustomizeDiff: resourceDatabaseBackupCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseBackupImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the `filess_database` to back up",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Backup description",
+			},
+			"retain_until": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  "RFC 3339 timestamp (e.g. `2026-12-31T00:00:00Z`) until which the backup is kept. Updated in place; when unset the backend retention policy applies",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Backup status",
+			},
+			"size_bytes": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the snapshot in bytes",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Backup creation timestamp",
+			},
+			"completed_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp at which the snapshot completed",
+			},
+		},
+	}
+}
+
+func resourceDatabaseBackupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId := d.Get("database_id").(string)
+
+	requestBody := map[string]interface{}{
+		"description": d.Get("description").(string),
+	}
+
+	// Un retain_until ya vencido (p. ej. al recrear un backup que ha expirado)
+	// no se envía y el nuevo backup sigue la política por defecto
+	var diags diag.Diagnostics
+	if v, ok := d.GetOk("retain_until"); ok {
+		if isPastInstant(v.(string)) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "retain_until is in the past",
+				Detail:   fmt.Sprintf("retain_until %s has already passed, so the new backup of database %s follows the backend retention policy. Set a future retain_until to keep it longer.", v.(string), databaseId),
+			})
+		} else {
+			requestBody["retainUntil"] = v.(string)
+		}
+	}
+
+	resp, err := c.Post("/api/v1/databases/"+databaseId+"/backups", requestBody)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error creating backup of database %s: %w", databaseId, err))
+	}
+
+	data := unwrapResponseObject(resp.Data, "backup")
+	if data["id"] == nil {
+		return diag.Errorf("the API did not return the ID of the backup created for database %s", databaseId)
+	}
+	backupId := idToString(data["id"])
+	d.SetId(databaseId + "/" + backupId)
+
+	tflog.Info(ctx, "Database backup started", map[string]interface{}{
+		"database_id": databaseId,
+		"backup_id":   backupId,
+	})
+
+	if err := waitForDatabaseBackup(ctx, c, databaseId, backupId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return append(diags, resourceDatabaseBackupRead(ctx, d, m)...)
+}
+
+func resourceDatabaseBackupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId, backupId, err := parseDatabaseBackupID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.Get(fmt.Sprintf("/api/v1/databases/%s/backups/%s", databaseId, backupId))
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "backup")
+
+	d.Set("database_id", databaseId)
+	d.Set("description", data["description"])
+	d.Set("status", data["status"])
+	if size, ok := data["sizeBytes"].(float64); ok {
+		d.Set("size_bytes", int(size))
+	}
+	if createdAt, ok := data["createdAt"]; ok {
+		d.Set("created_at", createdAt)
+	}
+	if completedAt, ok := data["completedAt"]; ok {
+		d.Set("completed_at", completedAt)
+	}
+
+	// Se conserva el formato de la configuración si el instante es el mismo, y
+	// también un retain_until ya vencido que no se envió al crear el backup,
+	// para que los siguientes planes no intenten aplicarlo
+	retainUntil, _ := data["retainUntil"].(string)
+	configured := d.Get("retain_until").(string)
+	if !sameInstant(retainUntil, configured) && !isPastInstant(configured) {
+		d.Set("retain_until", retainUntil)
+	}
+
+	return nil
+}
+
+func resourceDatabaseBackupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId, backupId, err := parseDatabaseBackupID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("retain_until") {
+		// null devuelve el backup a la política de retención por defecto
+		var retainUntil interface{}
+		if v, ok := d.GetOk("retain_until"); ok {
+			retainUntil = v.(string)
+		}
+
+		requestBody := map[string]interface{}{
+			"retainUntil": retainUntil,
+		}
+		if _, err := c.Patch(fmt.Sprintf("/api/v1/databases/%s/backups/%s", databaseId, backupId), requestBody); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating backup %s of database %s: %w", backupId, databaseId, err))
+		}
+	}
+
+	return resourceDatabaseBackupRead(ctx, d, m)
+}
+
+func resourceDatabaseBackupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId, backupId, err := parseDatabaseBackupID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	path := fmt.Sprintf("/api/v1/databases/%s/backups/%s", databaseId, backupId)
+	if _, err := c.Delete(path); err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// El snapshot se elimina del almacenamiento de forma asíncrona
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"deleting"},
+		Target:     []string{"deleted"},
+		MinTimeout: 5 * time.Second,
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get(path)
+			if err != nil {
+				if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+					return struct{}{}, "deleted", nil
+				}
+				return nil, "", err
+			}
+			return resp.Data, "deleting", nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for backup %s of database %s to be deleted: %s", backupId, databaseId, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceDatabaseBackupImport acepta database_id/backup_id
+func resourceDatabaseBackupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseDatabaseBackupID(d.Id()); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceDatabaseBackupCustomizeDiff rechaza en el plan un retain_until que
+// ya ha pasado cuando se cambia en un backup existente. Al crear o recrear un
+// backup expirado no se rechaza para no bloquear el apply
+func resourceDatabaseBackupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("retain_until") || !d.NewValueKnown("retain_until") {
+		return nil
+	}
+
+	value := d.Get("retain_until").(string)
+	if value == "" {
+		return nil
+	}
+
+	retainUntil, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fmt.Errorf("retain_until: %w", err)
+	}
+	if !retainUntil.After(time.Now()) {
+		return fmt.Errorf("retain_until: %s is in the past", value)
+	}
+	return nil
+}
+
+func parseDatabaseBackupID(id string) (string, string, error) {
+	databaseId, backupId, ok := strings.Cut(id, "/")
+	if !ok || databaseId == "" || backupId == "" {
+		return "", "", fmt.Errorf("unexpected database backup ID %q, expected <database_id>/<backup_id>", id)
+	}
+	return databaseId, backupId, nil
+}
+
+func isPastInstant(value string) bool {
+	t, err := time.Parse(time.RFC3339, value)
+	return err == nil && !t.After(time.Now())
+}
+
+func sameInstant(a, b string) bool {
+	ta, err := time.Parse(time.RFC3339, a)
+	if err != nil {
+		return false
+	}
+	tb, err := time.Parse(time.RFC3339, b)
+	return err == nil && ta.Equal(tb)
+}
+
+// waitForDatabaseBackup espera a que termine el snapshot y, si falla, devuelve
+// el motivo informado por el backend
+func waitForDatabaseBackup(ctx context.Context, c *client.Client, databaseId, backupId string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    backupPendingStatuses,
+		Target:     []string{"completed"},
+		MinTimeout: 10 * time.Second,
+		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get(fmt.Sprintf("/api/v1/databases/%s/backups/%s", databaseId, backupId))
+			if err != nil {
+				return nil, "", err
+			}
+
+			data := unwrapResponseObject(resp.Data, "backup")
+			status, _ := data["status"].(string)
+
+			if isFailedStatus(status) {
+				reason, _ := data["failureReason"].(string)
+				if reason == "" {
+					reason = "no reason reported"
+				}
+				return nil, "", fmt.Errorf("backup %s of database %s %s: %s", backupId, databaseId, status, reason)
+			}
+
+			tflog.Debug(ctx, "Waiting for database backup", map[string]interface{}{
+				"database_id": databaseId,
+				"backup_id":   backupId,
+				"status":      status,
+			})
+			return data, status, nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for backup %s of database %s: %w", backupId, databaseId, err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Niveles de privilegio que admite la API para los usuarios adicionales
+var databaseUserRoles = []string{"readonly", "readwrite", "admin"}
+
+var databaseUsernamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,31}$`)
+
+func ResourceDatabaseUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseUserCreate,
+		ReadContext:   resourceDatabaseUserRead,
+		UpdateContext: resourceDatabaseUserUpdate,
+		DeleteContext: resourceDatabaseUserDelete,
+		CustomizeDiff: resourceDatabaseUserCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseUserImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the `filess_database` the user belongs to",
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringMatch(databaseUsernamePattern, "must start with a letter or underscore and contain at most 32 letters, digits or underscores"),
+					validation.StringNotInSlice([]string{"root"}, true),
+				),
+				Description: "Username. `root` is managed by `filess_database` and cannot be used",
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(databaseUserRoles, false),
+				Description:  "Privilege level of the user: `readonly`, `readwrite` or `admin`",
+			},
+			"password": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				ValidateFunc:  validation.StringLenBetween(12, 128),
+				ConflictsWith: []string{"password_rotation_trigger"},
+				Description:   "Password of the user. Generated by the API when not set",
+			},
+			"password_rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value (e.g. a timestamp) whose change rotates the generated password in place. Conflicts with `password`",
+			},
+			"connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Connection URI for the engine with the URL-escaped credentials of this user",
+			},
+			"jdbc_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "JDBC URL with the credentials of this user for engines that have a JDBC driver (MySQL, MariaDB, PostgreSQL), empty otherwise",
+			},
+		},
+	}
+}
+
+func resourceDatabaseUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	requestBody := map[string]interface{}{
+		"username": username,
+		"role":     d.Get("role").(string),
+	}
+	if v, ok := d.GetOk("password"); ok {
+		requestBody["password"] = v.(string)
+	}
+
+	if _, err := c.Post("/api/v1/databases/"+databaseId+"/users", requestBody); err != nil {
+		return diag.FromErr(fmt.Errorf("error creating user %s in database %s: %w", username, databaseId, err))
+	}
+
+	d.SetId(databaseId + "/" + username)
+
+	// El usuario se crea en el motor de forma asíncrona
+	if err := waitForDatabaseUser(ctx, c, databaseId, username, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDatabaseUserRead(ctx, d, m)
+}
+
+func resourceDatabaseUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId, username, err := parseDatabaseUserID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := c.Get("/api/v1/databases/" + databaseId)
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	data := resp.Data.(map[string]interface{})
+
+	user := findDatabaseUser(data["databaseUsers"], username)
+	if user == nil {
+		tflog.Warn(ctx, "Database user no longer exists, removing it from state", map[string]interface{}{
+			"database_id": databaseId,
+			"username":    username,
+		})
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("database_id", databaseId)
+	d.Set("username", username)
+	if role, _ := user["role"].(string); role != "" {
+		d.Set("role", role)
+	}
+
+	password, _ := user["password"].(string)
+	if password != "" {
+		d.Set("password", password)
+	} else {
+		password = d.Get("password").(string)
+	}
+
+	slug, err := databaseEngineSlug(c, data)
+	if err != nil {
+		tflog.Warn(ctx, "Could not resolve engine slug to build connection URIs", map[string]interface{}{
+			"database_id": databaseId,
+			"error":       err.Error(),
+		})
+	}
+
+	params := mapDatabaseParams(data["databaseParams"])
+	connectionURI, jdbcURL := buildConnectionURIs(slug, params["database_hostname"], params["database_service_port"], username, password)
+	d.Set("connection_uri", connectionURI)
+	d.Set("jdbc_url", jdbcURL)
+
+	return nil
+}
+
+func resourceDatabaseUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+	path := fmt.Sprintf("/api/v1/databases/%s/users/%s", databaseId, url.PathEscape(username))
+
+	// Solo se envía la contraseña si viene de la configuración, no si es la
+	// generada por la API
+	passwordChanged := d.HasChange("password") && !d.GetRawConfig().GetAttr("password").IsNull()
+
+	if d.HasChange("role") || passwordChanged {
+		requestBody := map[string]interface{}{
+			"role": d.Get("role").(string),
+		}
+		if passwordChanged {
+			requestBody["password"] = d.Get("password").(string)
+		}
+
+		if _, err := c.Patch(path, requestBody); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating user %s in database %s: %w", username, databaseId, err))
+		}
+	}
+
+	if d.HasChange("password_rotation_trigger") {
+		oldPassword, _ := d.GetChange("password")
+		if err := rotateUserPassword(ctx, c, databaseId, username, oldPassword.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			restorePasswordRotationTrigger(d)
+			return diag.FromErr(err)
+		}
+
+		tflog.Info(ctx, "Database user password rotated", map[string]interface{}{
+			"database_id": databaseId,
+			"username":    username,
+		})
+	}
+
+	return resourceDatabaseUserRead(ctx, d, m)
+}
+
+func resourceDatabaseUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	databaseId := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	_, err := c.Delete(fmt.Sprintf("/api/v1/databases/%s/users/%s", databaseId, url.PathEscape(username)))
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceDatabaseUserCustomizeDiff marca como desconocidas las credenciales
+// que cambiarán al rotar o cambiar la contraseña
+func resourceDatabaseUserCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("password_rotation_trigger") {
+		if err := d.SetNewComputed("password"); err != nil {
+			return err
+		}
+	}
+	if d.HasChanges("password", "password_rotation_trigger") {
+		if err := d.SetNewComputed("connection_uri"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("jdbc_url")
+	}
+	return nil
+}
+
+// resourceDatabaseUserImport acepta database_id/username
+func resourceDatabaseUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseDatabaseUserID(d.Id()); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func parseDatabaseUserID(id string) (string, string, error) {
+	databaseId, username, ok := strings.Cut(id, "/")
+	if !ok || databaseId == "" || username == "" {
+		return "", "", fmt.Errorf("unexpected database user ID %q, expected <database_id>/<username>", id)
+	}
+	return databaseId, username, nil
+}
+
+// waitForDatabaseUser espera a que el usuario aparezca en la base de datos con
+// su contraseña
+func waitForDatabaseUser(ctx context.Context, c *client.Client, databaseId, username string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"creating"},
+		Target:     []string{"ready"},
+		MinTimeout: 5 * time.Second,
+		Timeout:    timeout,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := c.Get("/api/v1/databases/" + databaseId)
+			if err != nil {
+				return nil, "", err
+			}
+
+			data, ok := resp.Data.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("unexpected database response format")
+			}
+
+			user := findDatabaseUser(data["databaseUsers"], username)
+			if password, _ := user["password"].(string); password == "" {
+				return data, "creating", nil
+			}
+			return data, "ready", nil
+		},
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for user %s in database %s: %w", username, databaseId, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importScopedResource resuelve el ID de import de un objeto de una
+// organización y namespace y fija el ID, organization_slug y namespace_slug.
+// Los dos slugs son Required y ForceNew, así que el import falla si no se
+// pueden determinar en lugar de planificar un reemplazo
+func importScopedResource(c *client.Client, d *schema.ResourceData, basePath, kind, responseKey string) error {
+	id, organizationSlug, namespaceSlug, err := resolveScopedImportID(c, d.Id(), basePath, kind)
+	if err != nil {
+		return err
+	}
+
+	// Con un ID numérico los slugs solo se pueden sacar de la API
+	if organizationSlug == "" || namespaceSlug == "" {
+		resp, err := c.Get(basePath + "/" + id)
+		if err != nil {
+			return fmt.Errorf("error reading %s %s: %w", kind, id, err)
+		}
+
+		data := unwrapResponseObject(resp.Data, responseKey)
+		organizationSlug, _ = data["organizationSlug"].(string)
+		namespaceSlug, _ = data["namespaceSlug"].(string)
+		if organizationSlug == "" || namespaceSlug == "" {
+			return fmt.Errorf("cannot determine the organization and namespace of %s %s, import it as <organization_slug>/<namespace_slug>/<name> instead", kind, id)
+		}
+	}
+
+	d.SetId(id)
+	if err := d.Set("organization_slug", organizationSlug); err != nil {
+		return err
+	}
+	return d.Set("namespace_slug", namespaceSlug)
+}
+
+// resolveScopedImportID resuelve el ID de un objeto de una organización y
+// namespace (bases de datos, IP whitelists...) importado por ID numérico o como
+// organization_slug/namespace_slug/name, listando los objetos de basePath. Los
+// slugs solo se devuelven en el segundo caso
+func resolveScopedImportID(c *client.Client, importId, basePath, kind string) (string, string, string, error) {
+	if _, err := strconv.ParseUint(importId, 10, 64); err == nil {
+		return importId, "", "", nil
+	}
+
+	parts := strings.Split(importId, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected import ID %q, expected <%s_id> or <organization_slug>/<namespace_slug>/<name>", importId, strings.ReplaceAll(kind, " ", "_"))
+	}
+
+	query := url.Values{}
+	query.Set("organizationSlug", parts[0])
+	query.Set("namespaceSlug", parts[1])
+
+	resp, err := c.Get(basePath + "?" + query.Encode())
+	if err != nil {
+		return "", "", "", fmt.Errorf("error listing %ss in %s/%s: %w", kind, parts[0], parts[1], err)
+	}
+
+	items, _ := resp.Data.([]interface{})
+	var matches []string
+	for _, raw := range items {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := item["name"].(string); name == parts[2] {
+			matches = append(matches, idToString(item["id"]))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", "", "", fmt.Errorf("%s %q not found in %s/%s", kind, parts[2], parts[0], parts[1])
+	case 1:
+		return matches[0], parts[0], parts[1], nil
+	default:
+		return "", "", "", fmt.Errorf("%s name %q is ambiguous in %s/%s, import it by ID instead (one of: %s)", kind, parts[2], parts[0], parts[1], strings.Join(matches, ", "))
+	}
+}
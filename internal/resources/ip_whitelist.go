@@ -0,0 +1,237 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func ResourceIPWhitelist() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceIPWhitelistCreate,
+		ReadContext:   resourceIPWhitelistRead,
+		UpdateContext: resourceIPWhitelistUpdate,
+		DeleteContext: resourceIPWhitelistDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceIPWhitelistImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"organization_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Organization slug",
+			},
+			"namespace_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Namespace slug",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "IP whitelist name",
+			},
+			"entry": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Allowed network ranges",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cidr": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDR,
+							Description:  "Network range in CIDR notation (e.g. `203.0.113.0/24`, `2001:db8::/32`). Use `/32` or `/128` for a single address",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Entry description",
+						},
+					},
+				},
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "IP whitelist creation timestamp",
+			},
+		},
+	}
+}
+
+func resourceIPWhitelistCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	requestBody := map[string]interface{}{
+		"organizationSlug": d.Get("organization_slug").(string),
+		"namespaceSlug":    d.Get("namespace_slug").(string),
+		"name":             d.Get("name").(string),
+		"entries":          expandIPWhitelistEntries(d.Get("entry").(*schema.Set)),
+	}
+
+	resp, err := c.Post("/api/v1/ip-whitelists", requestBody)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "ipWhitelist")
+	if data["id"] == nil {
+		return diag.Errorf("the API did not return the ID of the created IP whitelist")
+	}
+	d.SetId(idToString(data["id"]))
+
+	return resourceIPWhitelistRead(ctx, d, m)
+}
+
+func resourceIPWhitelistRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	resp, err := c.Get("/api/v1/ip-whitelists/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "ipWhitelist")
+
+	d.Set("name", data["name"])
+	if v, ok := data["organizationSlug"].(string); ok && v != "" {
+		d.Set("organization_slug", v)
+	}
+	if v, ok := data["namespaceSlug"].(string); ok && v != "" {
+		d.Set("namespace_slug", v)
+	}
+	if createdAt, ok := data["createdAt"]; ok {
+		d.Set("created_at", createdAt)
+	}
+
+	if err := d.Set("entry", flattenIPWhitelistEntries(data["entries"])); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceIPWhitelistUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	if d.HasChanges("name", "entry") {
+		requestBody := map[string]interface{}{
+			"name":    d.Get("name").(string),
+			"entries": expandIPWhitelistEntries(d.Get("entry").(*schema.Set)),
+		}
+
+		if _, err := c.Patch("/api/v1/ip-whitelists/"+d.Id(), requestBody); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating IP whitelist %s: %w", d.Id(), err))
+		}
+	}
+
+	return resourceIPWhitelistRead(ctx, d, m)
+}
+
+func resourceIPWhitelistDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	_, err := c.Delete("/api/v1/ip-whitelists/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceIPWhitelistImport acepta un ID numérico o
+// organization_slug/namespace_slug/name
+func resourceIPWhitelistImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*meta.Meta).Client
+
+	if err := importScopedResource(c, d, "/api/v1/ip-whitelists", "IP whitelist", "ipWhitelist"); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// validateCIDR acepta solo rangos en notación CIDR cuya dirección sea la de
+// red, para que el valor guardado por el backend coincida con la configuración
+func validateCIDR(v interface{}, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	ip, network, err := net.ParseCIDR(value)
+	if err != nil {
+		if net.ParseIP(value) != nil {
+			return nil, []error{fmt.Errorf("%s: %q is a single address, use %q", k, value, singleAddressCIDR(value))}
+		}
+		return nil, []error{fmt.Errorf("%s: %q is not a valid CIDR range: %w", k, value, err)}
+	}
+
+	if !ip.Equal(network.IP) {
+		return nil, []error{fmt.Errorf("%s: %q has host bits set, did you mean %q?", k, value, network.String())}
+	}
+
+	return nil, nil
+}
+
+func singleAddressCIDR(address string) string {
+	if net.ParseIP(address).To4() != nil {
+		return address + "/32"
+	}
+	return address + "/128"
+}
+
+func expandIPWhitelistEntries(entries *schema.Set) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, entries.Len())
+	for _, raw := range entries.List() {
+		entry := raw.(map[string]interface{})
+		result = append(result, map[string]interface{}{
+			"cidr":        entry["cidr"].(string),
+			"description": entry["description"].(string),
+		})
+	}
+	return result
+}
+
+func flattenIPWhitelistEntries(raw interface{}) []interface{} {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+
+	entries := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		cidr, _ := entry["cidr"].(string)
+		description, _ := entry["description"].(string)
+		entries = append(entries, map[string]interface{}{
+			"cidr":        cidr,
+			"description": description,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCIDR(t *testing.T) {
+	cases := []struct {
+		value   string
+		wantErr string
+	}{
+		{value: "10.0.0.0/8"},
+		{value: "203.0.113.7/32"},
+		{value: "0.0.0.0/0"},
+		{value: "2001:db8::/32"},
+		{value: "2001:db8::1/128"},
+		{value: "203.0.113.7", wantErr: `"203.0.113.7" is a single address, use "203.0.113.7/32"`},
+		{value: "2001:db8::1", wantErr: `"2001:db8::1" is a single address, use "2001:db8::1/128"`},
+		{value: "10.0.0.1/8", wantErr: `"10.0.0.1/8" has host bits set, did you mean "10.0.0.0/8"?`},
+		{value: "10.0.0.0/33", wantErr: `"10.0.0.0/33" is not a valid CIDR range`},
+		{value: "office", wantErr: `"office" is not a valid CIDR range`},
+		{value: "", wantErr: `"" is not a valid CIDR range`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			_, errs := validateCIDR(tc.value, "cidr")
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
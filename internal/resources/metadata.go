@@ -0,0 +1,163 @@
+package resources
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+)
+
+// Tipos de billable items que se pueden escalar in-place
+const (
+	billableItemKindCPU       = "cpu"
+	billableItemKindMemory    = "memory"
+	billableItemKindStorage   = "storage"
+	billableItemKindBandwidth = "bandwidth"
+)
+
+type billableItemMetadata struct {
+	ID          string
+	Name        string
+	Description string
+	UnitSize    float64
+	Min         int
+	Max         int
+	Required    bool
+}
+
+// Kind clasifica el billable item por su nombre y devuelve una cadena vacía
+// para los items que no se pueden escalar (setup, región...)
+func (b billableItemMetadata) Kind() string {
+	name := strings.ToLower(b.Name)
+	switch {
+	case strings.Contains(name, "cpu"):
+		return billableItemKindCPU
+	case strings.Contains(name, "memory"):
+		return billableItemKindMemory
+	case strings.Contains(name, "storage"):
+		return billableItemKindStorage
+	case strings.Contains(name, "bandwidth"), strings.Contains(name, "network"):
+		return billableItemKindBandwidth
+	}
+	return ""
+}
+
+type databaseCreateMetadata struct {
+	BillableItems []billableItemMetadata
+}
+
+func (md *databaseCreateMetadata) billableItem(id string) (billableItemMetadata, bool) {
+	for _, item := range md.BillableItems {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return billableItemMetadata{}, false
+}
+
+func (md *databaseCreateMetadata) billableItemByName(name string) (billableItemMetadata, bool) {
+	for _, item := range md.BillableItems {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return billableItemMetadata{}, false
+}
+
+// describeBillableItems lista los billable items disponibles para los mensajes de error
+func (md *databaseCreateMetadata) describeBillableItems() string {
+	descriptions := make([]string, len(md.BillableItems))
+	for i, item := range md.BillableItems {
+		descriptions[i] = fmt.Sprintf("%s (%s, %d-%d)", item.ID, item.Name, item.Min, item.Max)
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// getDatabaseCreateMetadata devuelve la create metadata del engine y la región,
+// cacheada por instancia del provider como los catálogos
+func getDatabaseCreateMetadata(c *client.Client, engineId, regionId string) (*databaseCreateMetadata, error) {
+	query := url.Values{}
+	query.Set("engineId", engineId)
+	query.Set("regionId", regionId)
+
+	cache := catalogCacheFor(c)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if md, ok := cache.metadata[query.Encode()]; ok {
+		return md, nil
+	}
+
+	resp, err := c.Get("/api/v1/databases/create/metadata?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := resp.Data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected create metadata response format")
+	}
+
+	md := &databaseCreateMetadata{}
+	items, _ := data["billableItems"].([]interface{})
+	for _, raw := range items {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		md.BillableItems = append(md.BillableItems, parseBillableItemMetadata(idToString(item["id"]), item))
+	}
+
+	if cache.metadata == nil {
+		cache.metadata = map[string]*databaseCreateMetadata{}
+	}
+	cache.metadata[query.Encode()] = md
+	return md, nil
+}
+
+// parseBillableItemMetadata lee un billable item tal y como lo devuelven la
+// create metadata y los planes de las bases de datos
+func parseBillableItemMetadata(id string, item map[string]interface{}) billableItemMetadata {
+	min, _ := item["min"].(float64)
+	max, _ := item["max"].(float64)
+	name, _ := item["name"].(string)
+	description, _ := item["description"].(string)
+	unitSize, _ := item["unitSize"].(float64)
+	required, _ := item["required"].(bool)
+	return billableItemMetadata{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		UnitSize:    unitSize,
+		Min:         int(min),
+		Max:         int(max),
+		Required:    required,
+	}
+}
+
+// idToString normaliza los IDs de la API, que pueden llegar como string o float64
+func idToString(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		return fmt.Sprintf("%.0f", val)
+	case string:
+		return val
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// unwrapResponseObject devuelve el objeto de la respuesta, que según el
+// endpoint viene directamente en data o envuelto bajo una clave
+// (p. ej. {"ipWhitelist": {...}})
+func unwrapResponseObject(raw interface{}, key string) map[string]interface{} {
+	data, ok := raw.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	if inner, ok := data[key].(map[string]interface{}); ok {
+		return inner
+	}
+	return data
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// A partir de este tiempo se avisa de que la provisión ha tardado más de lo normal
+const slowProvisioningThreshold = 15 * time.Minute
+
+type provisioningPhase struct {
+	Status   string
+	Duration time.Duration
+}
+
+// provisioningProgress registra los cambios de estado vistos mientras se espera
+// a una base de datos, para poder loguearlos y resumirlos
+type provisioningProgress struct {
+	databaseId     string
+	start          time.Time
+	phase          string
+	phaseStart     time.Time
+	phases         []provisioningPhase
+	billingPending bool
+}
+
+func newProvisioningProgress(databaseId string) *provisioningProgress {
+	now := time.Now()
+	return &provisioningProgress{
+		databaseId: databaseId,
+		start:      now,
+		phaseStart: now,
+	}
+}
+
+// observe loguea un cambio de estado y, la primera vez que aparece, la
+// facturación pendiente
+func (p *provisioningProgress) observe(ctx context.Context, status string, checkoutURL string) {
+	now := time.Now()
+
+	if status != p.phase {
+		fields := map[string]interface{}{
+			"database_id":   p.databaseId,
+			"status":        status,
+			"total_elapsed": now.Sub(p.start).Round(time.Second).String(),
+		}
+		if p.phase != "" {
+			elapsed := now.Sub(p.phaseStart)
+			p.phases = append(p.phases, provisioningPhase{Status: p.phase, Duration: elapsed})
+			fields["previous_status"] = p.phase
+			fields["previous_status_elapsed"] = elapsed.Round(time.Second).String()
+		}
+		tflog.Info(ctx, "Database provisioning status changed", fields)
+
+		// La primera fase cuenta desde el inicio de la espera
+		if p.phase != "" {
+			p.phaseStart = now
+		}
+		p.phase = status
+	}
+
+	if checkoutURL != "" && !p.billingPending {
+		p.billingPending = true
+		tflog.Info(ctx, "Database provisioning has pending billing", map[string]interface{}{
+			"database_id":         p.databaseId,
+			"stripe_checkout_url": checkoutURL,
+		})
+	} else if checkoutURL == "" && p.billingPending {
+		p.billingPending = false
+		tflog.Info(ctx, "Database billing completed", map[string]interface{}{
+			"database_id": p.databaseId,
+		})
+	}
+}
+
+// finish cierra la fase actual y loguea el resumen de la provisión. err es el
+// error que cortó la espera, si lo hay, para resumir también los timeouts y las
+// interrupciones
+func (p *provisioningProgress) finish(ctx context.Context, err error) {
+	now := time.Now()
+	if p.phase != "" {
+		p.phases = append(p.phases, provisioningPhase{Status: p.phase, Duration: now.Sub(p.phaseStart)})
+		p.phase = ""
+	}
+
+	fields := map[string]interface{}{
+		"database_id":   p.databaseId,
+		"total_elapsed": p.total().Round(time.Second).String(),
+		"phases":        p.summary(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Warn(ctx, "Database provisioning wait stopped", fields)
+		return
+	}
+	tflog.Info(ctx, "Database provisioning finished", fields)
+}
+
+func (p *provisioningProgress) total() time.Duration {
+	var total time.Duration
+	for _, phase := range p.phases {
+		total += phase.Duration
+	}
+	return total
+}
+
+func (p *provisioningProgress) summary() string {
+	parts := make([]string, len(p.phases))
+	for i, phase := range p.phases {
+		parts[i] = fmt.Sprintf("%s %s", phase.Status, phase.Duration.Round(time.Second))
+	}
+	return strings.Join(parts, " → ")
+}
+
+// slowDiagnostics devuelve un warning con el desglose por fases cuando la
+// provisión tardó más que slowProvisioningThreshold
+func (p *provisioningProgress) slowDiagnostics() diag.Diagnostics {
+	total := p.total()
+	if total < slowProvisioningThreshold {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Database provisioning took unusually long",
+		Detail:   fmt.Sprintf("Database %s spent %s provisioning: %s", p.databaseId, total.Round(time.Second), p.summary()),
+	}}
+}
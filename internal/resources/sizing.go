@@ -0,0 +1,237 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Atributos de alto nivel y el tipo de billable item al que corresponde cada uno
+var sizingAttributes = map[string]string{
+	"cpu_cores":      billableItemKindCPU,
+	"memory_gib":     billableItemKindMemory,
+	"storage_gib":    billableItemKindStorage,
+	"bandwidth_mbps": billableItemKindBandwidth,
+}
+
+// Tamaño y unidad al final de la descripción del billable item, p. ej.
+// "CPU Core 0.25", "Memory 0.5GiB" o "Network Bandwidth 1MB/s"
+var billableItemSizePattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([a-zA-Z]*)(?:/s)?\s*$`)
+
+// billableItemUnit devuelve cuánto aporta una unidad del billable item, en
+// cores, GiB o Mbps. Se toma del unitSize de la metadata o, si no viene, de su
+// descripción; false si la metadata no lo indica
+func billableItemUnit(b billableItemMetadata) (float64, bool) {
+	if b.UnitSize > 0 {
+		return b.UnitSize, true
+	}
+
+	match := billableItemSizePattern.FindStringSubmatch(b.Description)
+	if match == nil {
+		return 0, false
+	}
+	size, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || size <= 0 {
+		return 0, false
+	}
+
+	suffix := strings.ToLower(match[2])
+	switch b.Kind() {
+	case billableItemKindCPU:
+		switch suffix {
+		case "", "core", "cores", "vcore", "vcores":
+			return size, true
+		}
+	case billableItemKindMemory, billableItemKindStorage:
+		switch suffix {
+		case "g", "gb", "gib":
+			return size, true
+		case "m", "mb", "mib":
+			return size / 1024, true
+		case "t", "tb", "tib":
+			return size * 1024, true
+		}
+	case billableItemKindBandwidth:
+		switch suffix {
+		case "m", "mb", "mbps":
+			return size, true
+		case "g", "gb", "gbps":
+			return size * 1000, true
+		}
+	}
+	return 0, false
+}
+
+// buildSizedPlan traduce los atributos de tamaño a cantidades de billable items
+// y añade los items obligatorios (instancia, región...) con su cantidad mínima
+func buildSizedPlan(md *databaseCreateMetadata, sizing map[string]float64) ([]interface{}, error) {
+	var errs []string
+	var billableItems []interface{}
+
+	attrs := make([]string, 0, len(sizingAttributes))
+	for attr := range sizingAttributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+
+	for _, attr := range attrs {
+		kind := sizingAttributes[attr]
+		value := sizing[attr]
+
+		var candidates []billableItemMetadata
+		var available []string
+		unsized := false
+		for _, item := range md.BillableItems {
+			if item.Kind() != kind {
+				continue
+			}
+			unit, ok := billableItemUnit(item)
+			if !ok {
+				if item.Required {
+					errs = append(errs, fmt.Sprintf("%s: billable item %s (%s) is required but the create metadata does not describe its size", attr, item.ID, item.Name))
+					unsized = true
+				}
+				continue
+			}
+			candidates = append(candidates, item)
+			available = append(available, fmt.Sprintf("%s (%s each, %d-%d)", item.Name, strconv.FormatFloat(unit, 'f', -1, 64), item.Min, item.Max))
+		}
+		if unsized {
+			continue
+		}
+		if len(candidates) == 0 {
+			errs = append(errs, fmt.Sprintf("%s: no %s billable item with a known size is available for this engine and region", attr, kind))
+			continue
+		}
+
+		quantities, ok := sizeBillableItems(candidates, value)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: %s cannot be provisioned with the available billable items: %s", attr, strconv.FormatFloat(value, 'f', -1, 64), strings.Join(available, ", ")))
+			continue
+		}
+
+		for _, item := range candidates {
+			if quantity := quantities[item.ID]; quantity > 0 {
+				billableItems = append(billableItems, map[string]interface{}{
+					"billable_item_id": item.ID,
+					"name":             item.Name,
+					"quantity":         quantity,
+				})
+			}
+		}
+	}
+
+	for _, item := range md.BillableItems {
+		if !item.Required || item.Kind() != "" {
+			continue
+		}
+		billableItems = append(billableItems, map[string]interface{}{
+			"billable_item_id": item.ID,
+			"name":             item.Name,
+			"quantity":         max(item.Min, 1),
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"billable_items": billableItems,
+		},
+	}, nil
+}
+
+// sizeBillableItems reparte value entre los billable items de un mismo tipo.
+// Los obligatorios llevan siempre al menos su mínimo, los opcionales van a 0 o
+// dentro de su rango, y de las combinaciones exactas se elige la de menos
+// unidades
+func sizeBillableItems(items []billableItemMetadata, value float64) (map[string]int, bool) {
+	items = append([]billableItemMetadata(nil), items...)
+	units := map[string]float64{}
+	for _, item := range items {
+		units[item.ID], _ = billableItemUnit(item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return units[items[i].ID] > units[items[j].ID]
+	})
+
+	allowed := func(item billableItemMetadata, quantity int) bool {
+		if quantity == 0 {
+			return !item.Required
+		}
+		return quantity >= max(item.Min, 1) && (item.Max <= 0 || quantity <= item.Max)
+	}
+
+	var best map[string]int
+	bestTotal := 0
+	current := map[string]int{}
+
+	var search func(i int, remaining float64, total int)
+	search = func(i int, remaining float64, total int) {
+		if best != nil && total >= bestTotal {
+			return
+		}
+
+		item := items[i]
+		unit := units[item.ID]
+		exact := remaining / unit
+
+		// El último item tiene que cubrir exactamente lo que queda
+		if i == len(items)-1 {
+			quantity := math.Round(exact)
+			if math.Abs(exact-quantity) > 1e-9 || quantity < 0 || !allowed(item, int(quantity)) {
+				return
+			}
+			current[item.ID] = int(quantity)
+			if best == nil || total+int(quantity) < bestTotal {
+				best = make(map[string]int, len(current))
+				for id, q := range current {
+					best[id] = q
+				}
+				bestTotal = total + int(quantity)
+			}
+			return
+		}
+
+		highest := int(math.Floor(exact + 1e-9))
+		if item.Max > 0 {
+			highest = min(highest, item.Max)
+		}
+		for quantity := highest; quantity >= 0; quantity-- {
+			if !allowed(item, quantity) {
+				continue
+			}
+			current[item.ID] = quantity
+			search(i+1, remaining-float64(quantity)*unit, total+quantity)
+		}
+		delete(current, item.ID)
+	}
+	search(0, value, 0)
+
+	return best, best != nil
+}
+
+// sizingFromPlan calcula los atributos de alto nivel a partir de las cantidades
+// del plan y la metadata de sus billable items
+func sizingFromPlan(quantities map[string]int, items map[string]billableItemMetadata) map[string]float64 {
+	totals := map[string]float64{}
+	for id, quantity := range quantities {
+		item := items[id]
+		unit, ok := billableItemUnit(item)
+		if !ok {
+			continue
+		}
+
+		for attr, kind := range sizingAttributes {
+			if item.Kind() == kind {
+				totals[attr] += float64(quantity) * unit
+			}
+		}
+	}
+	return totals
+}
@@ -0,0 +1,162 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBillableItemUnit(t *testing.T) {
+	cases := []struct {
+		name        string
+		description string
+		unitSize    float64
+		want        float64
+		wantOK      bool
+	}{
+		{name: "cpu_core_500m", description: "CPU Core 0.25", want: 0.25, wantOK: true},
+		{name: "cpu_core_2", description: "CPU Core 2 vCores", want: 2, wantOK: true},
+		{name: "memory_500MiB", description: "Memory 0.5GiB", want: 0.5, wantOK: true},
+		{name: "memory_512MiB", description: "Memory 512MiB", want: 0.5, wantOK: true},
+		{name: "db_storage_500MiB", description: "Storage 0.5GiB", want: 0.5, wantOK: true},
+		{name: "db_storage_1TiB", description: "Storage 1TiB", want: 1024, wantOK: true},
+		{name: "network_bandwidth_1M", description: "Network Bandwidth 1MB/s", want: 1, wantOK: true},
+		{name: "network_bandwidth_1G", description: "Network Bandwidth 1Gbps", want: 1000, wantOK: true},
+		{name: "cpu_core_500m", description: "CPU Core 0.25", unitSize: 0.5, want: 0.5, wantOK: true},
+		{name: "cpu_core_500m", description: "CPU Core"},
+		{name: "memory_500MiB", description: "Memory 0.5 units"},
+		{name: "db_instance", description: "Database Setup"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			got, ok := billableItemUnit(billableItemMetadata{Name: tc.name, Description: tc.description, UnitSize: tc.unitSize})
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("billableItemUnit(%q, %q) = %v, %v, want %v, %v", tc.name, tc.description, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+// Billable items de la tabla de examples/README.md
+var testBillableItems = []billableItemMetadata{
+	{ID: "6", Name: "db_storage_500MiB", Description: "Storage 0.5GiB", Min: 1, Max: 50, Required: true},
+	{ID: "7", Name: "network_bandwidth_1M", Description: "Network Bandwidth 1MB/s", Min: 100, Max: 400, Required: true},
+	{ID: "8", Name: "choose_region", Description: "Choose Region", Min: 1, Max: 1, Required: true},
+	{ID: "10", Name: "cpu_core_500m", Description: "CPU Core 0.25", Min: 1, Max: 16, Required: true},
+	{ID: "12", Name: "db_instance", Description: "Database Setup", Min: 1, Max: 1, Required: true},
+	{ID: "13", Name: "memory_500MiB", Description: "Memory 0.5GiB", Min: 1, Max: 8, Required: true},
+}
+
+func TestBuildSizedPlan(t *testing.T) {
+	items := append([]billableItemMetadata{
+		{ID: "11", Name: "cpu_core_2", Description: "CPU Core 2", Min: 0, Max: 4},
+	}, testBillableItems...)
+
+	unsizedMemory := append([]billableItemMetadata(nil), testBillableItems...)
+	unsizedMemory[len(unsizedMemory)-1].Description = "Memory"
+
+	cases := []struct {
+		desc    string
+		items   []billableItemMetadata
+		sizing  map[string]float64
+		want    map[string]int
+		wantErr string
+	}{
+		{
+			desc:   "request example",
+			sizing: map[string]float64{"cpu_cores": 0.5, "memory_gib": 2, "storage_gib": 10, "bandwidth_mbps": 100},
+			want:   map[string]int{"6": 20, "7": 100, "8": 1, "10": 2, "12": 1, "13": 4},
+		},
+		{
+			desc:   "half GiB of storage",
+			sizing: map[string]float64{"cpu_cores": 0.25, "memory_gib": 0.5, "storage_gib": 0.5, "bandwidth_mbps": 100},
+			want:   map[string]int{"6": 1, "7": 100, "8": 1, "10": 1, "12": 1, "13": 1},
+		},
+		{
+			desc:   "larger optional item keeps the required one",
+			sizing: map[string]float64{"cpu_cores": 4, "memory_gib": 1, "storage_gib": 1, "bandwidth_mbps": 200},
+			want:   map[string]int{"6": 2, "7": 200, "8": 1, "10": 8, "11": 1, "12": 1, "13": 2},
+		},
+		{
+			desc:   "beyond the maximum of the required item",
+			sizing: map[string]float64{"cpu_cores": 6, "memory_gib": 1, "storage_gib": 1, "bandwidth_mbps": 100},
+			want:   map[string]int{"6": 2, "7": 100, "8": 1, "10": 8, "11": 2, "12": 1, "13": 2},
+		},
+		{
+			desc:    "below the minimum",
+			sizing:  map[string]float64{"cpu_cores": 0.25, "memory_gib": 0.5, "storage_gib": 0.5, "bandwidth_mbps": 50},
+			wantErr: "bandwidth_mbps: 50 cannot be provisioned with the available billable items: network_bandwidth_1M (1 each, 100-400)",
+		},
+		{
+			desc:    "above the maximum",
+			sizing:  map[string]float64{"cpu_cores": 0.25, "memory_gib": 8, "storage_gib": 0.5, "bandwidth_mbps": 100},
+			wantErr: "memory_gib: 8 cannot be provisioned with the available billable items: memory_500MiB (0.5 each, 1-8)",
+		},
+		{
+			desc:    "not a multiple",
+			sizing:  map[string]float64{"cpu_cores": 0.3, "memory_gib": 1, "storage_gib": 1.2, "bandwidth_mbps": 100},
+			wantErr: "cpu_cores: 0.3 cannot be provisioned with the available billable items: cpu_core_2 (2 each, 0-4), cpu_core_500m (0.25 each, 1-16); storage_gib: 1.2 cannot be provisioned",
+		},
+		{
+			desc:    "required item without size",
+			items:   unsizedMemory,
+			sizing:  map[string]float64{"cpu_cores": 0.25, "memory_gib": 0.5, "storage_gib": 0.5, "bandwidth_mbps": 100},
+			wantErr: "billable item 13 (memory_500MiB) is required but the create metadata does not describe its size",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			md := &databaseCreateMetadata{BillableItems: items}
+			if tc.items != nil {
+				md.BillableItems = tc.items
+			}
+
+			plan, err := buildSizedPlan(md, tc.sizing)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := map[string]int{}
+			for _, raw := range plan[0].(map[string]interface{})["billable_items"].([]interface{}) {
+				item := raw.(map[string]interface{})
+				got[item["billable_item_id"].(string)] = item["quantity"].(int)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got billable items %v, want %v", got, tc.want)
+			}
+			for id, quantity := range tc.want {
+				if got[id] != quantity {
+					t.Errorf("billable item %s: got quantity %d, want %d (plan %v)", id, got[id], quantity, got)
+				}
+			}
+
+			// El plan generado tiene que pasar la misma validación que uno escrito a mano
+			if err := checkDatabasePlanItems(md, got); err != nil {
+				t.Errorf("built plan %v does not validate: %s", got, err)
+			}
+		})
+	}
+}
+
+func TestSizingFromPlan(t *testing.T) {
+	items := map[string]billableItemMetadata{}
+	for _, item := range testBillableItems {
+		items[item.ID] = item
+	}
+	quantities := map[string]int{"6": 20, "7": 100, "8": 1, "10": 2, "12": 1, "13": 4}
+
+	want := map[string]float64{"cpu_cores": 0.5, "memory_gib": 2, "storage_gib": 10, "bandwidth_mbps": 100}
+	got := sizingFromPlan(quantities, items)
+	for attr, value := range want {
+		if got[attr] != value {
+			t.Errorf("%s = %v, want %v", attr, got[attr], value)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package resources
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/ssh"
+)
+
+// Tamaño mínimo aceptado para claves RSA
+const minRSAKeyBits = 2048
+
+// Tipos de clave pública que acepta el backend
+var sshKeyTypes = []string{
+	ssh.KeyAlgoRSA,
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+}
+
+func ResourceSSHKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSSHKeyCreate,
+		ReadContext:   resourceSSHKeyRead,
+		UpdateContext: resourceSSHKeyUpdate,
+		DeleteContext: resourceSSHKeyDelete,
+		CustomizeDiff: resourceSSHKeyCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSSHKeyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"organization_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Organization slug",
+			},
+			"namespace_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Namespace slug",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "SSH key name",
+			},
+			"public_key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSSHPublicKey,
+				// Comentarios y espacios no cambian la clave
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					oldFingerprint, err := sshKeyFingerprint(old)
+					if err != nil {
+						return false
+					}
+					newFingerprint, err := sshKeyFingerprint(new)
+					return err == nil && oldFingerprint == newFingerprint
+				},
+				Description: "Public key in OpenSSH `authorized_keys` format (e.g. the contents of `~/.ssh/id_ed25519.pub`). Supported types are RSA (at least 2048 bits), Ed25519 and ECDSA",
+			},
+			"key_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Key type (e.g. `ssh-ed25519`, `ssh-rsa`, `ecdsa-sha2-nistp256`)",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 fingerprint of the key (`SHA256:...`), as shown by `ssh-keygen -lf`",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SSH key creation timestamp",
+			},
+		},
+	}
+}
+
+func resourceSSHKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	requestBody := map[string]interface{}{
+		"organizationSlug": d.Get("organization_slug").(string),
+		"namespaceSlug":    d.Get("namespace_slug").(string),
+		"name":             d.Get("name").(string),
+		"publicKey":        strings.TrimSpace(d.Get("public_key").(string)),
+	}
+
+	resp, err := c.Post("/api/v1/ssh-keys", requestBody)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "sshKey")
+	if data["id"] == nil {
+		return diag.Errorf("the API did not return the ID of the created SSH key")
+	}
+	d.SetId(idToString(data["id"]))
+
+	return resourceSSHKeyRead(ctx, d, m)
+}
+
+func resourceSSHKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	resp, err := c.Get("/api/v1/ssh-keys/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			tflog.Warn(ctx, "SSH key no longer exists, removing it from state", map[string]interface{}{
+				"ssh_key_id": d.Id(),
+			})
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "sshKey")
+
+	d.Set("name", data["name"])
+	if v, ok := data["organizationSlug"].(string); ok && v != "" {
+		d.Set("organization_slug", v)
+	}
+	if v, ok := data["namespaceSlug"].(string); ok && v != "" {
+		d.Set("namespace_slug", v)
+	}
+	if createdAt, ok := data["createdAt"]; ok {
+		d.Set("created_at", createdAt)
+	}
+
+	publicKey, _ := data["publicKey"].(string)
+	if publicKey == "" {
+		return nil
+	}
+	d.Set("public_key", publicKey)
+
+	// La huella se calcula en local para que sea comparable con ssh-keygen
+	// aunque el backend use otro formato
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Could not parse the SSH key returned by the API",
+			Detail:   fmt.Sprintf("SSH key %s: %s", d.Id(), err),
+		}}
+	}
+	d.Set("key_type", key.Type())
+	d.Set("fingerprint", ssh.FingerprintSHA256(key))
+
+	return nil
+}
+
+func resourceSSHKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	if d.HasChange("name") {
+		requestBody := map[string]interface{}{
+			"name": d.Get("name").(string),
+		}
+
+		if _, err := c.Patch("/api/v1/ssh-keys/"+d.Id(), requestBody); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating SSH key %s: %w", d.Id(), err))
+		}
+	}
+
+	return resourceSSHKeyRead(ctx, d, m)
+}
+
+func resourceSSHKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	_, err := c.Delete("/api/v1/ssh-keys/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSSHKeyImport acepta un ID numérico o
+// organization_slug/namespace_slug/name
+func resourceSSHKeyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*meta.Meta).Client
+
+	if err := importScopedResource(c, d, "/api/v1/ssh-keys", "SSH key", "sshKey"); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceSSHKeyCustomizeDiff muestra el tipo y la huella de la clave en el plan
+func resourceSSHKeyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("public_key") || (d.Id() != "" && !d.HasChange("public_key")) {
+		return nil
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(d.Get("public_key").(string)))
+	if err != nil {
+		return fmt.Errorf("public_key: %w", err)
+	}
+
+	if err := d.SetNew("key_type", key.Type()); err != nil {
+		return err
+	}
+	return d.SetNew("fingerprint", ssh.FingerprintSHA256(key))
+}
+
+func validateSSHPublicKey(v interface{}, k string) ([]string, []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	key, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(value))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: not a valid OpenSSH public key: %w", k, err)}
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, []error{fmt.Errorf("%s: expected a single public key", k)}
+	}
+
+	supported := false
+	for _, t := range sshKeyTypes {
+		supported = supported || key.Type() == t
+	}
+	if !supported {
+		return nil, []error{fmt.Errorf("%s: unsupported key type %q, expected one of: %s", k, key.Type(), strings.Join(sshKeyTypes, ", "))}
+	}
+
+	if cryptoKey, ok := key.(ssh.CryptoPublicKey); ok {
+		if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSAKeyBits {
+			return nil, []error{fmt.Errorf("%s: RSA key is %d bits, at least %d are required", k, rsaKey.N.BitLen(), minRSAKeyBits)}
+		}
+	}
+
+	return nil, nil
+}
+
+func sshKeyFingerprint(publicKey string) (string, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(key), nil
+}
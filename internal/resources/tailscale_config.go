@@ -0,0 +1,222 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/filess/terraform-provider-dedicated/internal/client"
+	"github.com/filess/terraform-provider-dedicated/internal/meta"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	tailscaleTagPattern            = regexp.MustCompile(`^tag:[a-zA-Z][a-zA-Z0-9-]*$`)
+	tailscaleHostnamePrefixPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+)
+
+func ResourceTailscaleConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceTailscaleConfigCreate,
+		ReadContext:   resourceTailscaleConfigRead,
+		UpdateContext: resourceTailscaleConfigUpdate,
+		DeleteContext: resourceTailscaleConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTailscaleConfigImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"organization_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Organization slug",
+			},
+			"namespace_slug": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Namespace slug",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Tailscale config name",
+			},
+			"auth_key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^tskey-`), "must be a Tailscale auth key (tskey-...)"),
+				Description:  "Tailscale auth key used to join databases to the tailnet. The API never returns it, so it is only sent on create and when it changes",
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(tailscaleTagPattern, "must be a Tailscale ACL tag (e.g. tag:db)"),
+				},
+				Description: "ACL tags advertised by the databases (e.g. `tag:db`). They must be allowed for the auth key",
+			},
+			"hostname_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(tailscaleHostnamePrefixPattern, "must be a lowercase DNS label of at most 32 characters"),
+				Description:  "Prefix of the machine names of the databases in the tailnet",
+			},
+			"ephemeral": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether databases join the tailnet as ephemeral nodes, removed automatically when they go offline",
+			},
+			"tailnet": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Tailnet DNS name (e.g. `example.ts.net`) the databases join",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Tailscale config creation timestamp",
+			},
+		},
+	}
+}
+
+func resourceTailscaleConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	requestBody := map[string]interface{}{
+		"organizationSlug": d.Get("organization_slug").(string),
+		"namespaceSlug":    d.Get("namespace_slug").(string),
+		"name":             d.Get("name").(string),
+		"authKey":          d.Get("auth_key").(string),
+		"tags":             d.Get("tags").(*schema.Set).List(),
+		"hostnamePrefix":   d.Get("hostname_prefix").(string),
+		"ephemeral":        d.Get("ephemeral").(bool),
+	}
+
+	resp, err := c.Post("/api/v1/tailscale-configs", requestBody)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "tailscaleConfig")
+	if data["id"] == nil {
+		return diag.Errorf("the API did not return the ID of the created Tailscale config")
+	}
+	d.SetId(idToString(data["id"]))
+
+	return resourceTailscaleConfigRead(ctx, d, m)
+}
+
+func resourceTailscaleConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	resp, err := c.Get("/api/v1/tailscale-configs/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	data := unwrapResponseObject(resp.Data, "tailscaleConfig")
+
+	d.Set("name", data["name"])
+	if v, ok := data["organizationSlug"].(string); ok && v != "" {
+		d.Set("organization_slug", v)
+	}
+	if v, ok := data["namespaceSlug"].(string); ok && v != "" {
+		d.Set("namespace_slug", v)
+	}
+	if v, ok := data["hostnamePrefix"].(string); ok {
+		d.Set("hostname_prefix", v)
+	}
+	if v, ok := data["ephemeral"].(bool); ok {
+		d.Set("ephemeral", v)
+	}
+	if v, ok := data["tailnet"].(string); ok {
+		d.Set("tailnet", v)
+	}
+	if createdAt, ok := data["createdAt"]; ok {
+		d.Set("created_at", createdAt)
+	}
+
+	// auth_key no se devuelve nunca, se conserva el valor del estado
+	if err := d.Set("tags", flattenStringList(data["tags"])); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceTailscaleConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	if d.HasChanges("name", "auth_key", "tags", "hostname_prefix", "ephemeral") {
+		requestBody := map[string]interface{}{
+			"name":           d.Get("name").(string),
+			"tags":           d.Get("tags").(*schema.Set).List(),
+			"hostnamePrefix": d.Get("hostname_prefix").(string),
+			"ephemeral":      d.Get("ephemeral").(bool),
+		}
+		if d.HasChange("auth_key") {
+			requestBody["authKey"] = d.Get("auth_key").(string)
+		}
+
+		if _, err := c.Patch("/api/v1/tailscale-configs/"+d.Id(), requestBody); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating Tailscale config %s: %w", d.Id(), err))
+		}
+	}
+
+	return resourceTailscaleConfigRead(ctx, d, m)
+}
+
+func resourceTailscaleConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*meta.Meta).Client
+
+	_, err := c.Delete("/api/v1/tailscale-configs/" + d.Id())
+	if err != nil {
+		if apiErr, ok := err.(*client.APIError); ok && apiErr.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceTailscaleConfigImport acepta un ID numérico o
+// organization_slug/namespace_slug/name
+func resourceTailscaleConfigImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	c := m.(*meta.Meta).Client
+
+	if err := importScopedResource(c, d, "/api/v1/tailscale-configs", "Tailscale config", "tailscaleConfig"); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func flattenStringList(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return []string{}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if v, ok := item.(string); ok {
+			values = append(values, v)
+		}
+	}
+	return values
+}